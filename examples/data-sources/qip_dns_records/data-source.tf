@@ -0,0 +1,11 @@
+# All SRV records of Active Directory in the zone
+data "qip_dns_records" "ldap" {
+  zone         = "example.com"
+  type         = "SRV"
+  owner_suffix = "_tcp.example.com"
+}
+
+# All records attached to an address object
+data "qip_dns_records" "host" {
+  address = "192.0.2.50"
+}
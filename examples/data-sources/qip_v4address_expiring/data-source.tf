@@ -0,0 +1,4 @@
+data "qip_v4address_expiring" "cleanup" {
+  subnet      = "192.0.2.0"
+  within_days = 7
+}
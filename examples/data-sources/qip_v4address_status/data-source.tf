@@ -0,0 +1,7 @@
+data "qip_v4address_status" "candidate" {
+  address = "192.0.2.23"
+}
+
+output "candidate_is_free" {
+  value = data.qip_v4address_status.candidate.state == "free"
+}
@@ -0,0 +1,12 @@
+resource "qip_v4address" "host" {
+  subnet      = "192.0.2.0"
+  name        = "my-host"
+  domain_name = "corp.example.com"
+}
+
+resource "qip_cname_record" "www" {
+  address     = qip_v4address.host.address
+  name        = "www"
+  domain_name = "corp.example.com"
+  target      = "my-host.corp.example.com"
+}
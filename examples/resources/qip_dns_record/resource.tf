@@ -0,0 +1,40 @@
+resource "qip_v4address" "mail" {
+  subnet      = "192.0.2.0"
+  name        = "mail"
+  domain_name = "corp.example.com"
+}
+
+resource "qip_dns_record" "mx" {
+  address     = qip_v4address.mail.address
+  type        = "MX"
+  domain_name = "corp.example.com"
+
+  mx {
+    priority = 10
+    host     = "mail.corp.example.com"
+  }
+}
+
+resource "qip_dns_record" "spf" {
+  address     = qip_v4address.mail.address
+  type        = "TXT"
+  domain_name = "corp.example.com"
+
+  txt {
+    value = "v=spf1 mx -all"
+  }
+}
+
+resource "qip_dns_record" "submission" {
+  address     = qip_v4address.mail.address
+  type        = "SRV"
+  name        = "_submission._tcp"
+  domain_name = "corp.example.com"
+
+  srv {
+    priority = 0
+    weight   = 1
+    port     = 587
+    target   = "mail.corp.example.com"
+  }
+}
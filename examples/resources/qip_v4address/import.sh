@@ -1 +1,8 @@
+# By address
 terraform import qip_v4address.address 192.0.2.23
+
+# By subnet and address
+terraform import qip_v4address.address 192.0.2.0/192.0.2.23
+
+# By FQDN of the object, fails when more than one object uses it
+terraform import qip_v4address.address my-example.corp.example.com
@@ -15,4 +15,22 @@ resource "qip_v4address" "full" {
   object_class = "Virtual Server"
   description  = "Example System"
   domain_name  = "corp.example.com"
+  expires_at   = "2024-12-31T00:00:00Z"
+
+  # Reverse lookup of the object itself
+  publish_ptr = "INTERNAL"
+  ptr_ttl     = 3600
+
+  duplicate_check = "fqdn"
+}
+
+resource "qip_v4address" "allocated" {
+  subnet = "192.0.2.0"
+  name   = "my-allocated-example"
+
+  allocation {
+    strategy = "highest"
+    offset   = 10
+    exclude  = ["192.0.2.254", "192.0.2.200-192.0.2.210"]
+  }
 }
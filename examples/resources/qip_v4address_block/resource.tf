@@ -0,0 +1,12 @@
+resource "qip_v4address_block" "k8s_nodes" {
+  subnet       = "192.0.2.0"
+  size         = 8
+  alignment    = 8
+  name_pattern = "k8s-node-{index}"
+
+  description = "Kubernetes nodes"
+}
+
+output "k8s_node_addresses" {
+  value = qip_v4address_block.k8s_nodes.addresses
+}
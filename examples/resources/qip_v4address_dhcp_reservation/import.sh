@@ -0,0 +1 @@
+terraform import qip_v4address_dhcp_reservation.lab_device 192.0.2.23
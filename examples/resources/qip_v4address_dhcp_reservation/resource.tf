@@ -0,0 +1,10 @@
+resource "qip_v4address" "lab_device" {
+  subnet = "192.0.2.0"
+  name   = "lab-device"
+}
+
+resource "qip_v4address_dhcp_reservation" "lab_device" {
+  address     = qip_v4address.lab_device.address
+  mac_address = "00:11:22:aa:bb:cc"
+  dhcp_server = "dhcp1.example.com"
+}
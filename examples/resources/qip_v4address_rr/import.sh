@@ -0,0 +1,2 @@
+# By address, type and FQDN of the record
+terraform import qip_v4address_rr.other_record 192.0.2.42/A/other-example.int.example.com
@@ -14,6 +14,13 @@ resource "qip_v4address_rr" "other_record" {
   name        = "other-example"
   address     = qip_v4address.address.address
   domain_name = qip_v4address.address.domain_name
+
+  # Short TTL ahead of a migration, only published in the internal view
+  ttl        = 300
+  publishing = "INTERNAL"
+
+  # QIP creates the PTR in the reverse zone, it is deleted together with the record
+  create_ptr = true
 }
 
 resource "qip_v4address_rr" "manually" {
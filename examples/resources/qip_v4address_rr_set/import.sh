@@ -0,0 +1,2 @@
+# By address of the object
+terraform import qip_v4address_rr_set.records 192.0.2.42
@@ -0,0 +1,28 @@
+resource "qip_v4address" "address" {
+  subnet = "192.0.2.0"
+  name   = "my-example"
+}
+
+# All other additional records of the object are deleted
+resource "qip_v4address_rr_set" "records" {
+  address = qip_v4address.address.address
+
+  record {
+    owner = "*.my-example.${qip_v4address.address.domain_name}"
+    type  = "A"
+    value = qip_v4address.address.address
+  }
+
+  record {
+    owner = "alias.${qip_v4address.address.domain_name}"
+    type  = "CNAME"
+    value = "my-example.${qip_v4address.address.domain_name}"
+  }
+
+  record {
+    owner = "my-example.${qip_v4address.address.domain_name}"
+    type  = "MX"
+    value = "10 my-example.${qip_v4address.address.domain_name}"
+    ttl   = 300
+  }
+}
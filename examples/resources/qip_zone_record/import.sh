@@ -0,0 +1,5 @@
+# By zone, type and owner relative to the zone, fails when more than one record matches
+terraform import qip_zone_record.ldap corp.example.com/SRV/_ldap._tcp.site1._sites
+
+# Record at the zone apex
+terraform import qip_zone_record.verification corp.example.com/TXT/@
@@ -0,0 +1,44 @@
+resource "qip_zone_record" "verification" {
+  zone = "corp.example.com"
+  type = "TXT"
+  ttl  = 300
+
+  txt {
+    value = "google-site-verification=abc123"
+  }
+}
+
+resource "qip_zone_record" "ldap" {
+  zone = "corp.example.com"
+  type = "SRV"
+  name = "_ldap._tcp.site1._sites"
+
+  srv {
+    priority = 0
+    weight   = 100
+    port     = 389
+    target   = "dc1.corp.example.com"
+  }
+}
+
+# PTR in a reverse zone, for an address not managed as object
+resource "qip_zone_record" "reverse" {
+  zone = "2.0.192.in-addr.arpa"
+  type = "PTR"
+  name = "99"
+
+  ptr {
+    target = "legacy.corp.example.com"
+  }
+}
+
+# IPv6 address of a host, IPv6 addresses are not managed as objects
+resource "qip_zone_record" "ipv6" {
+  zone = "corp.example.com"
+  type = "AAAA"
+  name = "web"
+
+  aaaa {
+    address = "2001:db8::10"
+  }
+}
@@ -0,0 +1,263 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/dnsname"
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip"
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/rr"
+)
+
+// dnsRecordsFilters are the filter attributes of qip_dns_records, at least one selecting records in QIP is required.
+var dnsRecordsFilters = []string{"address", "owner", "zone"}
+
+// dnsRecordsInfraTypes are the infrastructure types records can be listed for.
+var dnsRecordsInfraTypes = []string{
+	rr.InfraTypeObject, rr.InfraTypeV6Address, rr.InfraTypeZone,
+	rr.InfraTypeV4ReverseZone, rr.InfraTypeV6ReverseZone, rr.InfraTypeNode,
+}
+
+func dataSourceDNSRecords() *schema.Resource {
+	return &schema.Resource{
+		Description: "DNS records in QIP matching a set of filters, with the data decoded per record type. " +
+			"Default records generated by QIP are not listed.",
+
+		ReadContext: dataSourceDNSRecordsRead,
+
+		Schema: map[string]*schema.Schema{
+			"address": {
+				Description: "Only list records of the IPv4 or IPv6 address object.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.Any(
+					validation.IsIPv4Address, validation.IsIPv6Address)),
+				AtLeastOneOf: dnsRecordsFilters,
+			},
+			"owner": {
+				Description:      "Only list records with exactly this owner FQDN.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: validateHostname(true),
+				AtLeastOneOf:     dnsRecordsFilters,
+			},
+			"owner_suffix": {
+				Description: "Only list records with an owner within this domain, including the domain itself. " +
+					"The suffix is matched on whole labels.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: validateDomainName,
+			},
+			"type": {
+				Description: "Only list records of this type. (e.g. `" + rr.RRTypeTXT + "`)",
+				Type:        schema.TypeString,
+				Optional:    true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{
+					rr.RRTypeA, rr.RRTypeAAAA, rr.RRTypeCNAME, rr.RRTypeMX, rr.RRTypePTR, rr.RRTypeSRV, rr.RRTypeTXT,
+				}, true)),
+			},
+			"zone": {
+				Description: "Only list records of the zone infrastructure, records of address objects in the zone " +
+					"are not included. Reverse zones (`in-addr.arpa` and `ip6.arpa`) are detected by their name.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: validateDomainName,
+				AtLeastOneOf:     dnsRecordsFilters,
+			},
+			"infra_type": {
+				Description: "Only list records of this infrastructure type, one of `" +
+					strings.Join(dnsRecordsInfraTypes, "`, `") + "`. Defaults to the type matching `address` or `zone`.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(dnsRecordsInfraTypes, false)),
+			},
+			"records": {
+				Description: "List of matching records, sorted as returned by QIP.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: schemaDNSRecordsRecord(),
+				},
+			},
+		},
+	}
+}
+
+// schemaDNSRecordsRecord returns the attributes of a record listed by qip_dns_records.
+func schemaDNSRecordsRecord() map[string]*schema.Schema {
+	computed := func(valueType schema.ValueType, description string) *schema.Schema {
+		return &schema.Schema{Description: description, Type: valueType, Computed: true}
+	}
+
+	computedList := func(description string, attributes map[string]*schema.Schema) *schema.Schema {
+		return &schema.Schema{
+			Description: description,
+			Type:        schema.TypeList,
+			Computed:    true,
+			Elem:        &schema.Resource{Schema: attributes},
+		}
+	}
+
+	return map[string]*schema.Schema{
+		"owner":         computed(schema.TypeString, "FQDN of the record owner."),
+		"type":          computed(schema.TypeString, "Type of the record."),
+		"ttl":           computed(schema.TypeInt, "TTL of the record in seconds, `-1` for the default TTL of the zone."),
+		"publishing":    computed(schema.TypeString, "DNS views the record is published in."),
+		"infra_type":    computed(schema.TypeString, "Type of the infrastructure the record belongs to."),
+		"infra_address": computed(schema.TypeString, "Address of the infrastructure, for address objects."),
+		"infra_fqdn":    computed(schema.TypeString, "FQDN of the infrastructure, for zones and nodes."),
+		"data1":         computed(schema.TypeString, "Raw data field 1 of the record in QIP."),
+		"data2":         computed(schema.TypeString, "Raw data field 2 of the record in QIP."),
+		"data3":         computed(schema.TypeString, "Raw data field 3 of the record in QIP."),
+		"data4":         computed(schema.TypeString, "Raw data field 4 of the record in QIP."),
+		"address":       computed(schema.TypeString, "Address of an `A` or `AAAA` record."),
+		"target":        computed(schema.TypeString, "Target hostname of a `CNAME` or `PTR` record."),
+		"txt":           computed(schema.TypeString, "Value of a `TXT` record, with all strings joined."),
+		"mx": computedList("Data of a `MX` record.", map[string]*schema.Schema{
+			"priority": computed(schema.TypeInt, "Priority of the mail exchanger."),
+			"host":     computed(schema.TypeString, "Hostname of the mail exchanger."),
+		}),
+		"srv": computedList("Data of a `SRV` record.", map[string]*schema.Schema{
+			"priority": computed(schema.TypeInt, "Priority of the target."),
+			"weight":   computed(schema.TypeInt, "Relative weight of targets with the same priority."),
+			"port":     computed(schema.TypeInt, "Port of the service on the target."),
+			"target":   computed(schema.TypeString, "Hostname providing the service."),
+		}),
+	}
+}
+
+// expandDNSRecordsQuery returns the search query of the filters.
+//
+//nolint:forcetypeassert
+func expandDNSRecordsQuery(d *schema.ResourceData) *rr.Query {
+	query := &rr.Query{
+		InfraType:   d.Get("infra_type").(string),
+		Address:     d.Get("address").(string),
+		FQDN:        dnsname.Canonical(d.Get("zone").(string)),
+		Owner:       dnsname.Canonical(d.Get("owner").(string)),
+		OwnerSuffix: d.Get("owner_suffix").(string),
+		RRType:      strings.ToUpper(d.Get("type").(string)),
+	}
+
+	if query.InfraType == "" {
+		switch {
+		case query.Address != "":
+			query.InfraType = rr.InfraTypeObject
+
+			if addr, err := netip.ParseAddr(query.Address); err == nil && addr.Is6() {
+				query.InfraType = rr.InfraTypeV6Address
+			}
+		case query.FQDN != "":
+			query.InfraType = rr.ForZoneName(query.FQDN).Type
+		}
+	}
+
+	if query.InfraType == rr.InfraTypeV6Address {
+		query.Address = rr.ForV6Address(query.Address).Address
+	}
+
+	return query
+}
+
+// flattenDNSRecordsRecord returns the listed attributes of a record.
+//
+// The decoded attributes are left empty, when the data of the record can not be decoded.
+func flattenDNSRecordsRecord(record *rr.RR) map[string]any {
+	values := map[string]any{
+		"owner":         dnsname.Canonical(record.Owner),
+		"type":          record.RRType,
+		"ttl":           record.TTL,
+		"publishing":    record.Publishing,
+		"infra_type":    record.InfraType,
+		"infra_address": record.InfraAddr,
+		"infra_fqdn":    dnsname.Canonical(record.InfraFQDN),
+		"data1":         record.Data1,
+		"data2":         record.Data2,
+		"data3":         record.Data3,
+		"data4":         record.Data4,
+		"address":       "",
+		"target":        "",
+		"txt":           "",
+		"mx":            []any{},
+		"srv":           []any{},
+	}
+
+	switch record.RRType {
+	case rr.RRTypeA, rr.RRTypeAAAA:
+		values["address"] = record.Data1
+	case rr.RRTypeCNAME:
+		values["target"] = dnsname.Canonical(record.Data1)
+	case rr.RRTypeMX, rr.RRTypeSRV, rr.RRTypeTXT, rr.RRTypePTR:
+		data, err := flattenDNSRecordData(record)
+		if err != nil {
+			break
+		}
+
+		switch record.RRType {
+		case rr.RRTypeTXT:
+			values["txt"] = data["value"]
+		case rr.RRTypePTR:
+			values["target"] = data["target"]
+		default:
+			values[dnsRecordBlocks[record.RRType]] = []any{data}
+		}
+	}
+
+	return values
+}
+
+func dataSourceDNSRecordsRead(_ context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	query := expandDNSRecordsQuery(d)
+
+	records, err := rr.Search(meta.(*terraformClient).QIPClient, query) //nolint:forcetypeassert
+	if err != nil {
+		var notFoundErr *qip.HTTPNotFoundError
+		if !errors.As(err, &notFoundErr) {
+			return diag.Errorf("could not list DNS records: %s", err)
+		}
+
+		// QIP has no records matching the filters
+		records = nil
+	}
+
+	values := make([]map[string]any, 0, len(records))
+
+	for _, record := range records {
+		values = append(values, flattenDNSRecordsRecord(record))
+	}
+
+	d.SetId(strings.Join([]string{
+		query.InfraType, query.Address, query.FQDN, query.Owner, query.OwnerSuffix, query.RRType,
+	}, "/"))
+
+	err = d.Set("records", values)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
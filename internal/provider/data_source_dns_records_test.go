@@ -0,0 +1,125 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/rr"
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/test"
+)
+
+func TestExpandDNSRecordsQuery(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, dataSourceDNSRecords().Schema, map[string]any{
+		"address": "2001:0db8::0050",
+		"type":    "aaaa",
+	})
+
+	assert.Equal(t, &rr.Query{InfraType: rr.InfraTypeV6Address, Address: "2001:db8::50", RRType: rr.RRTypeAAAA},
+		expandDNSRecordsQuery(d))
+
+	d = schema.TestResourceDataRaw(t, dataSourceDNSRecords().Schema, map[string]any{
+		"zone": "2.0.192.in-addr.arpa.",
+	})
+
+	assert.Equal(t, &rr.Query{InfraType: rr.InfraTypeV4ReverseZone, FQDN: "2.0.192.in-addr.arpa"},
+		expandDNSRecordsQuery(d))
+}
+
+func TestDataSourceDNSRecordsRead(t *testing.T) {
+	c, cleanup := test.GetTestClient(t)
+	defer cleanup()
+
+	httpmock.RegisterResponderWithQuery("GET", test.QIPServer+"/api/v1/"+test.QIPOrg+"/rr.json",
+		"type=ZONE&fqdn=example.com&getDefaultRRs=false", httpmock.NewStringResponder(200, testZoneRecords))
+
+	d := schema.TestResourceDataRaw(t, dataSourceDNSRecords().Schema, map[string]any{
+		"zone":         "example.com",
+		"owner_suffix": "_tcp.example.com",
+	})
+
+	diags := dataSourceDNSRecordsRead(context.Background(), d, &terraformClient{QIPClient: c})
+	require.False(t, diags.HasError())
+	assert.Equal(t, "ZONE//example.com//_tcp.example.com/", d.Id())
+	assert.Equal(t, 2, d.Get("records.#"))
+	assert.Equal(t, "_ldap._tcp.example.com", d.Get("records.0.owner"))
+	assert.Equal(t, "389", d.Get("records.0.data3"))
+	assert.Equal(t, 389, d.Get("records.0.srv.0.port"))
+	assert.Equal(t, "dc2.example.com", d.Get("records.1.srv.0.target"))
+	assert.Equal(t, 0, d.Get("records.1.mx.#"))
+
+	// QIP returns 404 when no record matches, e.g. when checking that a name is not used
+	httpmock.RegisterResponderWithQuery("GET", test.QIPServer+"/api/v1/"+test.QIPOrg+"/rr.json",
+		"owner=free.example.com&getDefaultRRs=false", httpmock.NewStringResponder(404, ""))
+
+	d = schema.TestResourceDataRaw(t, dataSourceDNSRecords().Schema, map[string]any{
+		"owner": "free.example.com",
+	})
+
+	diags = dataSourceDNSRecordsRead(context.Background(), d, &terraformClient{QIPClient: c})
+	require.False(t, diags.HasError(), diags)
+	assert.NotEmpty(t, d.Id())
+	assert.Equal(t, 0, d.Get("records.#"))
+}
+
+func TestFlattenDNSRecordsRecord(t *testing.T) {
+	values := flattenDNSRecordsRecord(rr.NewTXTForObject("host.example.com", "192.0.2.50", "v=spf1 -all"))
+	assert.Equal(t, "v=spf1 -all", values["txt"])
+	assert.Equal(t, rr.InfraTypeObject, values["infra_type"])
+	assert.Equal(t, "192.0.2.50", values["infra_address"])
+
+	values = flattenDNSRecordsRecord(rr.NewCNAMEForObject("WWW.example.com.", "Host.example.com.", "192.0.2.50"))
+	assert.Equal(t, "www.example.com", values["owner"])
+	assert.Equal(t, "host.example.com", values["target"])
+
+	// Invalid data is only returned raw
+	record := rr.NewForObject("mail.example.com", rr.RRTypeMX, "192.0.2.50", "high", "mail.example.com")
+	values = flattenDNSRecordsRecord(record)
+	assert.Equal(t, "high", values["data1"])
+	assert.Equal(t, []any{}, values["mx"])
+}
+
+func TestAccDataSourceDNSRecords(t *testing.T) {
+	zone := getRequiredEnv(t, "QIP_TEST_ACC_ZONE")
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+					data "qip_dns_records" "test" {
+						zone = "` + zone + `"
+						type = "TXT"
+					}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("data.qip_dns_records.test", "id", stringRe("ZONE//"+zone+"///TXT")),
+				),
+			},
+		},
+	})
+}
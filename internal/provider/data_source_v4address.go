@@ -47,27 +47,7 @@ func dataSourceV4AddressRead(_ context.Context, d *schema.ResourceData, meta any
 
 	d.SetId(addr.ObjectAddr)
 
-	err = d.Set("subnet", addr.SubnetAddr)
-	if err != nil {
-		return diag.FromErr(err)
-	}
-
-	err = d.Set("name", addr.ObjectName)
-	if err != nil {
-		return diag.FromErr(err)
-	}
-
-	err = d.Set("description", addr.ObjectDesc)
-	if err != nil {
-		return diag.FromErr(err)
-	}
-
-	err = d.Set("object_class", addr.ObjectClass)
-	if err != nil {
-		return diag.FromErr(err)
-	}
-
-	err = d.Set("domain_name", addr.DomainName)
+	err = flattenV4Address(addr, d)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -0,0 +1,147 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/v4address"
+)
+
+const day = 24 * time.Hour
+
+// expiringNow returns the start of the window, replaced in tests.
+var expiringNow = time.Now
+
+func dataSourceV4AddressExpiring() *schema.Resource {
+	return &schema.Resource{
+		Description: "IPv4 address objects in QIP that expire within a time window, including already expired objects.",
+
+		ReadContext: dataSourceV4AddressExpiringRead,
+
+		Schema: map[string]*schema.Schema{
+			"subnet": {
+				Description:      "Only list objects of this subnet, by default the whole organization is searched.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: validateIPV4Address,
+			},
+			"within_days": {
+				Description:      "Number of days from now, objects expiring until then are listed.",
+				Type:             schema.TypeInt,
+				Required:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(0)),
+			},
+			"objects": {
+				Description: "List of expiring objects, sorted as returned by QIP.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"address": {
+							Description: "IPv4 address.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"subnet": {
+							Description: "Subnet of the IPv4 address.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"name": {
+							Description: "Hostname for the address.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"domain_name": {
+							Description: "DNS Zone of the address.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"expires_at": {
+							Description: "Expiry date of the address object as RFC3339 timestamp.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+//nolint:forcetypeassert
+func dataSourceV4AddressExpiringRead(_ context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	var (
+		client = meta.(*terraformClient).QIPClient
+		subnet = d.Get("subnet").(string)
+		days   = d.Get("within_days").(int)
+	)
+
+	query := &v4address.Query{SubnetAddr: subnet}
+
+	addrs, invalid, err := v4address.ListExpiring(client, query, expiringNow(), time.Duration(days)*day)
+	if err != nil {
+		return diag.Errorf("could not list expiring IPv4 objects: %s", err)
+	}
+
+	var diags diag.Diagnostics
+
+	for _, addr := range invalid {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "Expiry date of " + addr.ObjectAddr + " could not be parsed",
+			Detail:   fmt.Sprintf("The object is not listed, QIP returned expiredDate %q.", addr.ExpiredDate),
+		})
+	}
+
+	objects := make([]map[string]any, 0, len(addrs))
+
+	for _, addr := range addrs {
+		object := make(map[string]any)
+
+		for _, key := range []string{"address", "subnet", "name", "domain_name", "expires_at"} {
+			field := v4AddressFields[key]
+			object[key] = field.normalize(field.get(addr))
+		}
+
+		objects = append(objects, object)
+	}
+
+	scope := client.OrgName
+	if subnet != "" {
+		scope = subnet
+	}
+
+	d.SetId(scope + "/" + strconv.Itoa(days))
+
+	err = d.Set("objects", objects)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diags
+}
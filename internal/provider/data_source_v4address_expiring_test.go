@@ -0,0 +1,92 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/test"
+)
+
+func TestDataSourceV4AddressExpiringRead(t *testing.T) {
+	c, cleanup := test.GetTestClient(t)
+	defer cleanup()
+
+	now := expiringNow
+	expiringNow = func() time.Time { return time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC) }
+
+	t.Cleanup(func() { expiringNow = now })
+
+	httpmock.RegisterResponderWithQuery("GET", test.QIPServer+"/api/v1/"+test.QIPOrg+"/v4address.json",
+		"subnetAddress=192.0.2.0",
+		httpmock.NewStringResponder(200, `{"list":[
+			{"objectAddr":"192.0.2.50","subnetAddr":"192.0.2.0","objectName":"expired","expiredDate":"03/01/2024"},
+			{"objectAddr":"192.0.2.51","subnetAddr":"192.0.2.0","objectName":"last-day","expiredDate":"04/09/2024"},
+			{"objectAddr":"192.0.2.52","subnetAddr":"192.0.2.0","objectName":"too-late","expiredDate":"04/10/2024"},
+			{"objectAddr":"192.0.2.53","subnetAddr":"192.0.2.0","objectName":"never","expiredDate":"None"},
+			{"objectAddr":"192.0.2.54","subnetAddr":"192.0.2.0","objectName":"broken","expiredDate":"31.03.2024"}
+		]}`))
+
+	d := schema.TestResourceDataRaw(t, dataSourceV4AddressExpiring().Schema, map[string]any{
+		"subnet":      "192.0.2.0",
+		"within_days": 30,
+	})
+
+	diags := dataSourceV4AddressExpiringRead(context.Background(), d, &terraformClient{QIPClient: c})
+	require.Len(t, diags, 1)
+	assert.Equal(t, diag.Warning, diags[0].Severity)
+	assert.Contains(t, diags[0].Summary, "192.0.2.54")
+
+	assert.Equal(t, "192.0.2.0/30", d.Id())
+	assert.Equal(t, 2, d.Get("objects.#"))
+	assert.Equal(t, "expired", d.Get("objects.0.name"))
+	assert.Equal(t, "2024-03-01T00:00:00Z", d.Get("objects.0.expires_at"))
+	assert.Equal(t, "last-day", d.Get("objects.1.name"))
+}
+
+func TestAccDataSourceV4AddressExpiring(t *testing.T) {
+	subnet := getRequiredEnv(t, "QIP_TEST_ACC_RESOURCE_SUBNET")
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+					data "qip_v4address_expiring" "test" {
+						subnet      = "` + subnet + `"
+						within_days = 30
+					}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("data.qip_v4address_expiring.test", "id", stringRe(subnet+"/30")),
+				),
+			},
+		},
+	})
+}
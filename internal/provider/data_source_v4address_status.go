@@ -0,0 +1,105 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/v4address"
+)
+
+func dataSourceV4AddressStatus() *schema.Resource {
+	return &schema.Resource{
+		Description: "State of an IPv4 address in QIP: free, selected, in use or tombstoned.",
+
+		ReadContext: dataSourceV4AddressStatusRead,
+
+		Schema: map[string]*schema.Schema{
+			"address": {
+				Description:      "IPv4 address.",
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validateIPV4Address,
+			},
+			"state": {
+				Description: "State of the address. `free` when no object exists, `selected` when the address is reserved " +
+					"but no object was created yet, `in_use` when an object exists and `tombstoned` when the object " +
+					"was deleted but is still kept by QIP.",
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"subnet": {
+				Description: "Subnet of the object, empty when there is no object.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"name": {
+				Description: "Hostname of the object, empty when there is no object.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"domain_name": {
+				Description: "DNS Zone of the object, empty when there is no object.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+//nolint:forcetypeassert
+func dataSourceV4AddressStatusRead(_ context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	var (
+		client  = meta.(*terraformClient).QIPClient
+		address = d.Get("address").(string)
+	)
+
+	state, addr, err := v4address.Status(client, address)
+	if err != nil {
+		return diag.Errorf("could not query state of IPv4 address: %s", err)
+	}
+
+	values := map[string]any{
+		"state":       string(state),
+		"subnet":      "",
+		"name":        "",
+		"domain_name": "",
+	}
+
+	if addr != nil {
+		for _, key := range []string{"subnet", "name", "domain_name"} {
+			field := v4AddressFields[key]
+			values[key] = field.normalize(field.get(addr))
+		}
+	}
+
+	d.SetId(address)
+
+	for k, v := range values {
+		err = d.Set(k, v)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return nil
+}
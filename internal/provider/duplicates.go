@@ -0,0 +1,234 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/dnsname"
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip"
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/rr"
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/v4address"
+)
+
+// Modes for the duplicate_check attribute.
+const (
+	DuplicateCheckNone = "none"
+	DuplicateCheckName = "name"
+	DuplicateCheckFQDN = "fqdn"
+)
+
+// Policies for the duplicate_policy attribute, applied when QIP has more than one record matching the ID.
+const (
+	DuplicatePolicyError        = "error"
+	DuplicatePolicyAdoptFirst   = "adopt_first"
+	DuplicatePolicyRemoveExtras = "remove_extras"
+)
+
+// qipTrue is the value QIP expects for enabled flags of a V4Address.
+const qipTrue = "true"
+
+var ErrDuplicate = errors.New("duplicate found in QIP")
+
+// duplicateCheckFields is the v4address.Patch field mask of the flags set by applyDuplicateCheck.
+var duplicateCheckFields = []string{"isCheckDupName", "isCheckOnlyFQDNDups"}
+
+// schemaDuplicateCheck returns the duplicate_check attribute supporting the modes.
+func schemaDuplicateCheck(modes ...string) *schema.Schema {
+	description := "Check QIP for existing objects or RRs with the same name during plan. " +
+		"`" + DuplicateCheckNone + "` disables the check"
+
+	for _, mode := range modes {
+		switch mode {
+		case DuplicateCheckName:
+			description += ", `" + DuplicateCheckName + "` fails for any object with the same hostname in any domain"
+		case DuplicateCheckFQDN:
+			description += ", `" + DuplicateCheckFQDN + "` fails for objects or RRs with the same FQDN"
+		}
+	}
+
+	return &schema.Schema{
+		Description: description + ".",
+		Type:        schema.TypeString,
+		Optional:    true,
+		Default:     DuplicateCheckNone,
+		ValidateDiagFunc: validation.ToDiagFunc(
+			validation.StringInSlice(append([]string{DuplicateCheckNone}, modes...), false)),
+	}
+}
+
+// schemaDuplicatePolicy returns the duplicate_policy attribute.
+func schemaDuplicatePolicy() *schema.Schema {
+	return &schema.Schema{
+		Description: "Handling of duplicate records in QIP matching this resource. `" + DuplicatePolicyError +
+			"` fails, `" + DuplicatePolicyAdoptFirst + "` manages the first record and keeps the others, `" +
+			DuplicatePolicyRemoveExtras + "` manages the first record and deletes the others during the next apply. " +
+			"A warning is shown for duplicates unless the policy is `" + DuplicatePolicyError + "`.",
+		Type:     schema.TypeString,
+		Optional: true,
+		Default:  DuplicatePolicyError,
+		ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(
+			[]string{DuplicatePolicyError, DuplicatePolicyAdoptFirst, DuplicatePolicyRemoveExtras}, false)),
+	}
+}
+
+// selectDuplicate returns the single record to manage of records matching the same ID, and the other
+// records accepted by the policy.
+//
+// Nil is returned when no record matches. Nothing is changed in QIP, see removeDuplicates.
+func selectDuplicate(records []*rr.RR, policy string) (*rr.RR, []*rr.RR, diag.Diagnostics) {
+	if len(records) == 0 {
+		return nil, nil, nil
+	} else if len(records) == 1 {
+		return records[0], nil, nil
+	}
+
+	record := records[0]
+	summary := fmt.Sprintf("%d duplicate %s records found for %s", len(records), record.RRType, record.Owner)
+
+	switch policy {
+	case DuplicatePolicyAdoptFirst:
+		return record, records[1:], diag.Diagnostics{{
+			Severity: diag.Warning,
+			Summary:  summary,
+			Detail:   "The first record is managed, the others are kept in QIP.",
+		}}
+	case DuplicatePolicyRemoveExtras:
+		return record, records[1:], diag.Diagnostics{{
+			Severity: diag.Warning,
+			Summary:  summary,
+			Detail:   fmt.Sprintf("The first record is managed, %d duplicates are removed from QIP by the next apply.", len(records)-1),
+		}}
+	}
+
+	return nil, nil, diag.Errorf("%s: %s, set duplicate_policy to resolve", ErrNonUniqueRR, summary)
+}
+
+// removeDuplicates deletes the extra records returned by selectDuplicate, only used during apply.
+func removeDuplicates(client *qip.Client, extras []*rr.RR) error {
+	var errs []error
+
+	for _, extra := range extras {
+		errs = append(errs, rr.Delete(client, extra))
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("could not remove duplicate records: %w", err)
+	}
+
+	return nil
+}
+
+// applyDuplicateCheck passes the duplicate check mode to QIP, so it is also enforced during apply.
+func applyDuplicateCheck(mode string, addr *v4address.V4Address) {
+	addr.IsCheckDupName = ""
+	addr.IsCheckOnlyFQDNDups = ""
+
+	switch mode {
+	case DuplicateCheckName:
+		addr.IsCheckDupName = qipTrue
+	case DuplicateCheckFQDN:
+		addr.IsCheckDupName = qipTrue
+		addr.IsCheckOnlyFQDNDups = qipTrue
+	}
+}
+
+// findDuplicateObject searches for another object using the name, or the FQDN when domain is set.
+//
+// The object at ownAddress is ignored, an empty result means no duplicate was found.
+func findDuplicateObject(client *qip.Client, name, domain, ownAddress string) (*v4address.V4Address, error) {
+	addrs, err := v4address.List(client, &v4address.Query{
+		ObjectName: name,
+		DomainName: domain,
+	})
+	if err != nil {
+		var notFoundErr *qip.HTTPNotFoundError
+		if errors.As(err, &notFoundErr) {
+			return nil, nil //nolint:nilnil
+		}
+
+		return nil, err //nolint:wrapcheck
+	}
+
+	for _, addr := range addrs {
+		if addr.ObjectAddr == ownAddress || normalizeHostname(addr.ObjectName) != normalizeHostname(name) {
+			continue
+		}
+
+		if domain != "" && normalizeHostname(addr.DomainName) != normalizeHostname(domain) {
+			continue
+		}
+
+		return addr, nil
+	}
+
+	return nil, nil //nolint:nilnil
+}
+
+// findDuplicateRR searches for a record with the owner FQDN, except the record ignore.
+func findDuplicateRR(client *qip.Client, fqdn string, ignore *rr.RR) (*rr.RR, error) {
+	records, err := rr.LoadAllForOwner(client, fqdn)
+	if err != nil {
+		var notFoundErr *qip.HTTPNotFoundError
+		if errors.As(err, &notFoundErr) {
+			return nil, nil //nolint:nilnil
+		}
+
+		return nil, err //nolint:wrapcheck
+	}
+
+	for _, record := range records {
+		if ignore != nil && record.Equal(ignore) {
+			continue
+		}
+
+		if normalizeHostname(record.Owner) == normalizeHostname(fqdn) {
+			return record, nil
+		}
+	}
+
+	return nil, nil //nolint:nilnil
+}
+
+// checkDuplicateFQDN fails when an object or a RR other than the ignored ones uses the FQDN.
+func checkDuplicateFQDN(client *qip.Client, attribute, name, domain, ownAddress string, ownRecord *rr.RR) error {
+	fqdn := dnsname.Join(name, domain)
+
+	addr, err := findDuplicateObject(client, name, domain, ownAddress)
+	if err != nil {
+		return fmt.Errorf("could not check for duplicates: %w", err)
+	} else if addr != nil {
+		return fmt.Errorf("%w: %s: FQDN %q is already used by the object %s", ErrDuplicate, attribute, fqdn, addr.ObjectAddr)
+	}
+
+	record, err := findDuplicateRR(client, fqdn, ownRecord)
+	if err != nil {
+		return fmt.Errorf("could not check for duplicates: %w", err)
+	} else if record != nil && record.InfraAddr != ownAddress {
+		return fmt.Errorf("%w: %s: FQDN %q is already used by a %s record of %s",
+			ErrDuplicate, attribute, fqdn, record.RRType, record.InfraAddr)
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/rr"
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/test"
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/v4address"
+)
+
+func TestCheckDuplicateFQDN(t *testing.T) {
+	c, cleanup := test.GetTestClient(t)
+	defer cleanup()
+
+	baseURL := test.QIPServer + "/api/v1/" + test.QIPOrg
+
+	httpmock.RegisterResponder("GET", baseURL+"/v4address.json",
+		httpmock.NewStringResponder(200, `{"list":[
+			{"objectAddr":"192.0.2.10","objectName":"host","domainName":"example.com"},
+			{"objectAddr":"192.0.2.11","objectName":"host","domainName":"other.example.com"}]}`))
+	httpmock.RegisterResponder("GET", baseURL+"/rr.json", httpmock.NewStringResponder(404, ``))
+
+	// Own object is ignored
+	err := checkDuplicateFQDN(c, "name", "host", "example.com", "192.0.2.10", nil)
+	require.NoError(t, err)
+
+	err = checkDuplicateFQDN(c, "name", "HOST", "example.com.", "192.0.2.20", nil)
+	require.ErrorIs(t, err, ErrDuplicate)
+	assert.Contains(t, err.Error(), "name: ")
+	assert.Contains(t, err.Error(), "192.0.2.10")
+
+	// RR with the same owner
+	httpmock.RegisterResponder("GET", baseURL+"/v4address.json", httpmock.NewStringResponder(404, ``))
+	httpmock.RegisterResponder("GET", baseURL+"/rr.json",
+		httpmock.NewStringResponder(200, `{"list":[{"owner":"extra.example.com","classType":"IN","rrType":"A","data1":"192.0.2.30",
+			"infraType":"OBJECT","infraAddr":"192.0.2.30"}]}`))
+
+	err = checkDuplicateFQDN(c, "name", "extra", "example.com", "", nil)
+	require.ErrorIs(t, err, ErrDuplicate)
+	assert.Contains(t, err.Error(), "192.0.2.30")
+
+	err = checkDuplicateFQDN(c, "name", "extra", "example.com", "",
+		rr.NewAForObject("extra.example.com", "192.0.2.30"))
+	require.NoError(t, err)
+}
+
+func TestApplyDuplicateCheck(t *testing.T) {
+	addr := &v4address.V4Address{}
+
+	applyDuplicateCheck(DuplicateCheckFQDN, addr)
+	assert.Equal(t, "true", addr.IsCheckDupName)
+	assert.Equal(t, "true", addr.IsCheckOnlyFQDNDups)
+
+	applyDuplicateCheck(DuplicateCheckName, addr)
+	assert.Equal(t, "true", addr.IsCheckDupName)
+	assert.Empty(t, addr.IsCheckOnlyFQDNDups)
+
+	applyDuplicateCheck(DuplicateCheckNone, addr)
+	assert.Empty(t, addr.IsCheckDupName)
+}
+
+func TestSelectDuplicate(t *testing.T) {
+	first := rr.NewAForObject("www.example.com", "192.0.2.50")
+	second := rr.NewAForObject("www.example.com", "192.0.2.50")
+
+	record, extras, diags := selectDuplicate(nil, DuplicatePolicyError)
+	assert.Nil(t, record)
+	assert.Empty(t, extras)
+	assert.Empty(t, diags)
+
+	record, extras, diags = selectDuplicate([]*rr.RR{first}, DuplicatePolicyError)
+	assert.Same(t, first, record)
+	assert.Empty(t, extras)
+	assert.Empty(t, diags)
+
+	_, _, diags = selectDuplicate([]*rr.RR{first, second}, DuplicatePolicyError)
+	assert.True(t, diags.HasError())
+
+	for _, policy := range []string{DuplicatePolicyAdoptFirst, DuplicatePolicyRemoveExtras} {
+		record, extras, diags = selectDuplicate([]*rr.RR{first, second}, policy)
+		assert.Same(t, first, record, policy)
+		assert.Equal(t, []*rr.RR{second}, extras, policy)
+
+		if assert.Len(t, diags, 1, policy) {
+			assert.Equal(t, diag.Warning, diags[0].Severity, policy)
+		}
+	}
+}
+
+func TestRemoveDuplicates(t *testing.T) {
+	c, cleanup := test.GetTestClient(t)
+	defer cleanup()
+
+	httpmock.RegisterResponder("DELETE", test.QIPServer+"/api/v1/"+test.QIPOrg+"/rr",
+		httpmock.NewStringResponder(200, ""))
+
+	require.NoError(t, removeDuplicates(c, []*rr.RR{rr.NewAForObject("www.example.com", "192.0.2.50")}))
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+}
@@ -0,0 +1,232 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/dnsname"
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/v4address"
+)
+
+// qipEmptyValue is returned by QIP for some attributes that are not set.
+const qipEmptyValue = "None"
+
+// v4AddressField maps a schema attribute to a field of v4address.V4Address.
+type v4AddressField struct {
+	// get returns the field value of the object.
+	get func(addr *v4address.V4Address) string
+	// set stores a value in the object, nil for attributes that can not be written directly.
+	set func(addr *v4address.V4Address, value string)
+	// normalize returns the canonical form of a value, it is used for state and to compare values.
+	normalize func(value string) string
+	// mask is the name of the field written by set, as used by v4address.Patch.
+	mask string
+}
+
+// v4AddressFields lists all attributes managed for a V4Address, they are shared by resource and data source.
+var v4AddressFields = map[string]v4AddressField{
+	"address": {
+		get:       func(addr *v4address.V4Address) string { return addr.ObjectAddr },
+		normalize: normalizeValue,
+	},
+	"subnet": {
+		get:       func(addr *v4address.V4Address) string { return addr.SubnetAddr },
+		normalize: normalizeValue,
+	},
+	"name": {
+		get:       func(addr *v4address.V4Address) string { return addr.ObjectName },
+		set:       func(addr *v4address.V4Address, value string) { addr.ObjectName = value },
+		normalize: normalizeHostname,
+		mask:      "objectName",
+	},
+	"description": {
+		get:       func(addr *v4address.V4Address) string { return addr.ObjectDesc },
+		set:       func(addr *v4address.V4Address, value string) { addr.ObjectDesc = value },
+		normalize: normalizeValue,
+		mask:      "objectDesc",
+	},
+	"object_class": {
+		get:       func(addr *v4address.V4Address) string { return addr.ObjectClass },
+		set:       func(addr *v4address.V4Address, value string) { addr.ObjectClass = value },
+		normalize: normalizeValue,
+		mask:      "objectClass",
+	},
+	"domain_name": {
+		get:       func(addr *v4address.V4Address) string { return addr.DomainName },
+		set:       func(addr *v4address.V4Address, value string) { addr.DomainName = value },
+		normalize: normalizeHostname,
+		mask:      "domainName",
+	},
+	"publish_ptr": {
+		get:       func(addr *v4address.V4Address) string { return addr.PublishPTR },
+		set:       func(addr *v4address.V4Address, value string) { addr.PublishPTR = value },
+		normalize: normalizeValue,
+		mask:      "publishPTR",
+	},
+	"ptr_ttl": {
+		get:       func(addr *v4address.V4Address) string { return addr.PtrTTL },
+		set:       func(addr *v4address.V4Address, value string) { addr.PtrTTL = value },
+		normalize: normalizeValue,
+		mask:      "ptrTTL",
+	},
+	"expires_at": {
+		get: func(addr *v4address.V4Address) string {
+			expires, ok, err := addr.ExpiresAt()
+			if err != nil {
+				// Show the unknown format instead of hiding it
+				return addr.ExpiredDate
+			} else if !ok {
+				return ""
+			}
+
+			return expires.Format(time.RFC3339)
+		},
+		set: func(addr *v4address.V4Address, value string) {
+			expires, _ := time.Parse(time.RFC3339, value)
+			addr.SetExpiresAt(expires)
+		},
+		normalize: normalizeExpiresAt,
+		mask:      "expiredDate",
+	},
+}
+
+// flattenV4Address stores all managed attributes of the object in the state.
+func flattenV4Address(addr *v4address.V4Address, d *schema.ResourceData) error {
+	for key, field := range v4AddressFields {
+		err := d.Set(key, field.normalize(field.get(addr)))
+		if err != nil {
+			return fmt.Errorf("could not set %s: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+// expandV4Address applies all writable attributes from the configuration to the object.
+//
+// Identifying attributes like address and subnet are not changed.
+func expandV4Address(d *schema.ResourceData, addr *v4address.V4Address) {
+	expandV4AddressValues(d.Get, addr)
+}
+
+// expandV4AddressPrior returns the writable attributes of the prior state, as the base for v4address.Patch.
+func expandV4AddressPrior(d *schema.ResourceData) *v4address.V4Address {
+	addr := &v4address.V4Address{}
+
+	expandV4AddressValues(func(key string) any {
+		oldValue, _ := d.GetChange(key)
+
+		return oldValue
+	}, addr)
+
+	return addr
+}
+
+// expandV4AddressValues applies all writable attributes returned by get to the object.
+func expandV4AddressValues(get func(key string) any, addr *v4address.V4Address) {
+	for key, field := range v4AddressFields {
+		if field.set == nil {
+			continue
+		}
+
+		value, ok := get(key).(string)
+		if !ok {
+			continue
+		}
+
+		field.set(addr, field.normalize(value))
+	}
+
+	// QIP stores the hostname relative to the domain
+	addr.ObjectName = dnsname.Relative(addr.ObjectName, addr.DomainName)
+}
+
+// v4AddressFieldMask returns the v4address.Patch field mask of all attributes written by expandV4Address.
+func v4AddressFieldMask() []string {
+	var mask []string
+
+	for _, field := range v4AddressFields {
+		if field.set != nil {
+			mask = append(mask, field.mask)
+		}
+	}
+
+	sort.Strings(mask)
+
+	return mask
+}
+
+// normalizeValue trims the value and treats the QIP placeholder for unset values as empty.
+func normalizeValue(value string) string {
+	value = strings.TrimSpace(value)
+	if value == qipEmptyValue {
+		return ""
+	}
+
+	return value
+}
+
+// normalizeHostname returns a DNS name in its canonical form, see dnsname.Canonical.
+func normalizeHostname(value string) string {
+	return dnsname.Canonical(normalizeValue(value))
+}
+
+// suppressRelativeHostnameDiff ignores differences of a hostname relative to domain_name,
+// so a hostname accidentally configured with its domain does not cause a diff.
+func suppressRelativeHostnameDiff(_, oldValue, newValue string, d *schema.ResourceData) bool {
+	domain, _ := d.Get("domain_name").(string)
+
+	return dnsname.Relative(normalizeValue(oldValue), domain) == dnsname.Relative(normalizeValue(newValue), domain)
+}
+
+// normalizeExpiresAt returns the day of a RFC3339 timestamp in UTC, since QIP only stores the day.
+func normalizeExpiresAt(value string) string {
+	value = normalizeValue(value)
+
+	expires, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return value
+	}
+
+	return expires.UTC().Truncate(24 * time.Hour).Format(time.RFC3339) //nolint:gomnd
+}
+
+// normalizeMACAddress returns a MAC address in the format of v4address.NormalizeMACAddress, invalid values are kept.
+func normalizeMACAddress(value string) string {
+	value = normalizeValue(value)
+
+	if mac, err := v4address.NormalizeMACAddress(value); err == nil {
+		return mac
+	}
+
+	return value
+}
+
+// suppressNormalizedDiff returns a DiffSuppressFunc, that ignores differences after normalization.
+func suppressNormalizedDiff(normalize func(string) string) schema.SchemaDiffSuppressFunc {
+	return func(_, oldValue, newValue string, _ *schema.ResourceData) bool {
+		return normalize(oldValue) == normalize(newValue)
+	}
+}
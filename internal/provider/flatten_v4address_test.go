@@ -0,0 +1,85 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/v4address"
+)
+
+func TestFlattenV4Address(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, schemaV4Address(false), map[string]any{})
+
+	err := flattenV4Address(&v4address.V4Address{
+		ObjectAddr:  "192.0.2.50",
+		SubnetAddr:  "192.0.2.0",
+		ObjectName:  "Test-Host",
+		ObjectClass: "Server",
+		DomainName:  "Int.Example.com.",
+		ObjectDesc:  qipEmptyValue,
+		PublishPTR:  "NEVER",
+		PtrTTL:      "300",
+	}, d)
+	require.NoError(t, err)
+
+	assert.Equal(t, "192.0.2.50", d.Get("address"))
+	assert.Equal(t, "test-host", d.Get("name"))
+	assert.Equal(t, "int.example.com", d.Get("domain_name"))
+	assert.Equal(t, "", d.Get("description"))
+	assert.Equal(t, "Server", d.Get("object_class"))
+	assert.Equal(t, "NEVER", d.Get("publish_ptr"))
+	assert.Equal(t, "300", d.Get("ptr_ttl"))
+}
+
+func TestExpandV4Address(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, schemaV4Address(false), map[string]any{
+		"address":     "192.0.2.51",
+		"subnet":      "192.0.2.0",
+		"name":        "New-Name.example.com",
+		"domain_name": "example.com.",
+	})
+
+	addr := &v4address.V4Address{
+		ObjectAddr: "192.0.2.50",
+		SubnetAddr: "192.0.2.0",
+		ObjectName: "old-name",
+		MacAddr:    "00:11:22:33:44:55",
+	}
+
+	expandV4Address(d, addr)
+
+	assert.Equal(t, "192.0.2.50", addr.ObjectAddr, "identifying attributes are not expanded")
+	assert.Equal(t, "new-name", addr.ObjectName, "name is relative to the domain")
+	assert.Equal(t, "example.com", addr.DomainName)
+	assert.Equal(t, "Virtualized Server", addr.ObjectClass)
+	assert.Equal(t, "00:11:22:33:44:55", addr.MacAddr, "unmanaged attributes are kept")
+}
+
+func TestSuppressNormalizedDiff(t *testing.T) {
+	suppress := suppressNormalizedDiff(normalizeHostname)
+
+	assert.True(t, suppress("", "example.com", "Example.COM.", nil))
+	assert.False(t, suppress("", "example.com", "example.org", nil))
+	assert.True(t, suppress("", "xn--mller-kva.example.com", "Müller.example.com", nil))
+}
@@ -67,14 +67,30 @@ func New(version string) func() *schema.Provider {
 					Description: "Timeout of HTTP requests of the provider in seconds.",
 					Default:     qip.DefaultTimeout.Seconds(),
 				},
+				"validate_with_server": {
+					Type:     schema.TypeBool,
+					Optional: true,
+					Description: "Check object classes and domains against the values known by QIP during plan. " +
+						"The lists are loaded once per run.",
+					Default: false,
+				},
 			},
 			DataSourcesMap: map[string]*schema.Resource{
-				"qip_v4address": dataSourceV4Address(),
-				"qip_v4subnet":  dataSourceV4Subnet(),
+				"qip_dns_records":        dataSourceDNSRecords(),
+				"qip_v4address":          dataSourceV4Address(),
+				"qip_v4address_expiring": dataSourceV4AddressExpiring(),
+				"qip_v4address_status":   dataSourceV4AddressStatus(),
+				"qip_v4subnet":           dataSourceV4Subnet(),
 			},
 			ResourcesMap: map[string]*schema.Resource{
-				"qip_v4address":    resourceV4Address(),
-				"qip_v4address_rr": resourceV4AddressRR(),
+				"qip_cname_record":               resourceCNAMERecord(),
+				"qip_dns_record":                 resourceDNSRecord(),
+				"qip_v4address":                  resourceV4Address(),
+				"qip_v4address_block":            resourceV4AddressBlock(),
+				"qip_v4address_dhcp_reservation": resourceV4AddressDHCPReservation(),
+				"qip_v4address_rr":               resourceV4AddressRR(),
+				"qip_v4address_rr_set":           resourceV4AddressRRSet(),
+				"qip_zone_record":                resourceZoneRecord(),
 			},
 		}
 
@@ -86,6 +102,9 @@ func New(version string) func() *schema.Provider {
 
 type terraformClient struct {
 	QIPClient *qip.Client
+
+	validateWithServer bool
+	constraints        serverConstraints
 }
 
 func configure(_ string, _ *schema.Provider) func(context.Context, *schema.ResourceData) (any, diag.Diagnostics) {
@@ -102,6 +121,8 @@ func configure(_ string, _ *schema.Provider) func(context.Context, *schema.Resou
 			requestTimeout = d.Get("request_timeout").(int)
 		)
 
+		client.validateWithServer = d.Get("validate_with_server").(bool) //nolint:forcetypeassert
+
 		if server == "" || org == "" || username == "" || password == "" {
 			return nil, diag.Errorf("Unable to create QIP client: server, org, username and password must be set")
 		}
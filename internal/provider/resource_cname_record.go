@@ -0,0 +1,284 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/dnsname"
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip"
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/rr"
+)
+
+var ErrCNAMETargetNotFound = errors.New("CNAME target does not exist in QIP")
+
+func resourceCNAMERecord() *schema.Resource {
+	return &schema.Resource{
+		Description: "Managing a CNAME record attached to an IPv4 address object in QIP. " +
+			"The target must exist in QIP and the alias must not be used by any other record.",
+
+		CreateContext: resourceCNAMERecordCreate,
+		ReadContext:   resourceCNAMERecordRead,
+		UpdateContext: resourceCNAMERecordUpdate,
+		DeleteContext: resourceCNAMERecordDelete,
+
+		CustomizeDiff: resourceCNAMERecordCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"address": {
+				Description:      "IPv4 address of the object the record is attached to.",
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validateIPV4Address,
+			},
+			"name": {
+				Description:      "Alias name of the record, relative to `domain_name`. (e.g. `www`)",
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validateHostname(true),
+				DiffSuppressFunc: suppressNormalizedDiff(normalizeHostname),
+			},
+			"domain_name": {
+				Description:      "DNS Zone of the alias.",
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validateDomainName,
+				DiffSuppressFunc: suppressNormalizedDiff(normalizeHostname),
+			},
+			"target": {
+				Description:      "FQDN the alias points to, usually the canonical name of the object.",
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validateDomainName,
+				DiffSuppressFunc: suppressNormalizedDiff(normalizeHostname),
+			},
+			"fqdn": {
+				Description: "FQDN of the alias.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// expandCNAMERecord returns the record for the configuration.
+//
+//nolint:forcetypeassert
+func expandCNAMERecord(d cnameRecordData) *rr.RR {
+	return rr.NewCNAMEForObject(
+		dnsname.Join(d.Get("name").(string), d.Get("domain_name").(string)),
+		dnsname.Canonical(d.Get("target").(string)),
+		d.Get("address").(string),
+	)
+}
+
+// cnameRecordData is implemented by schema.ResourceData and schema.ResourceDiff.
+type cnameRecordData interface {
+	Get(key string) any
+	Id() string
+}
+
+// checkCNAMERecord checks that the target exists and the alias does not conflict with other records.
+func checkCNAMERecord(client *qip.Client, d cnameRecordData) error {
+	record := expandCNAMERecord(d)
+
+	exists, err := cnameTargetExists(client, record.Data1)
+	if err != nil {
+		return fmt.Errorf("could not check CNAME target: %w", err)
+	} else if !exists {
+		return fmt.Errorf("%w: target: %s", ErrCNAMETargetNotFound, record.Data1)
+	}
+
+	var ownRecord *rr.RR
+
+	if d.Id() != "" {
+		ownRecord, err = parseV4AddressRRID(d.Id())
+		if err != nil {
+			return err
+		}
+	}
+
+	err = rr.CheckConflict(client, record, ownRecord)
+	if err != nil {
+		return fmt.Errorf("name: %w", err)
+	}
+
+	return nil
+}
+
+// cnameTargetExists checks for an object or any record with the FQDN.
+func cnameTargetExists(client *qip.Client, target string) (bool, error) {
+	_, err := findV4AddressByFQDN(client, target)
+	if err == nil || errors.Is(err, ErrFQDNAmbiguous) {
+		return true, nil
+	} else if !errors.Is(err, ErrFQDNNotFound) {
+		return false, err
+	}
+
+	records, err := rr.LoadAllForOwner(client, target)
+	if err != nil {
+		var notFoundErr *qip.HTTPNotFoundError
+		if errors.As(err, &notFoundErr) {
+			return false, nil
+		}
+
+		return false, err //nolint:wrapcheck
+	}
+
+	return len(records) > 0, nil
+}
+
+// loadCNAMERecord returns the record of the ID, nil is returned when it is not found.
+func loadCNAMERecord(d *schema.ResourceData, meta any) (*rr.RR, error) {
+	if d.Id() == "" {
+		return nil, ErrIDRequiredToLoad
+	}
+
+	idRecord, err := parseV4AddressRRID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	return loadSingleRR(meta.(*terraformClient).QIPClient, idRecord) //nolint:forcetypeassert
+}
+
+func resourceCNAMERecordCustomizeDiff(_ context.Context, d *schema.ResourceDiff, meta any) error {
+	if d.Id() != "" && !d.HasChanges("name", "domain_name", "target") {
+		return nil
+	}
+
+	for _, key := range []string{"address", "name", "domain_name", "target"} {
+		if !d.NewValueKnown(key) {
+			// Checked again during apply
+			return nil
+		}
+	}
+
+	return checkCNAMERecord(meta.(*terraformClient).QIPClient, d) //nolint:forcetypeassert
+}
+
+func resourceCNAMERecordCreate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*terraformClient).QIPClient //nolint:forcetypeassert
+
+	err := checkCNAMERecord(client, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	record := expandCNAMERecord(d)
+
+	err = rr.Create(client, record)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(formatV4AddressRRID(record))
+
+	tflog.Trace(ctx, "Created CNAME record "+record.Owner)
+
+	return resourceCNAMERecordRead(ctx, d, meta)
+}
+
+func resourceCNAMERecordRead(_ context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	record, err := loadCNAMERecord(d, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if record == nil {
+		// Deleted outside of Terraform, plan to create it again
+		id := d.Id()
+		d.SetId("")
+
+		return diag.Diagnostics{removedRecordWarning(id)}
+	}
+
+	values := map[string]any{
+		"address": record.InfraAddr,
+		"target":  dnsname.Canonical(record.Data1),
+		"fqdn":    dnsname.Canonical(record.Owner),
+	}
+
+	for k, v := range values {
+		err = d.Set(k, v)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return nil
+}
+
+func resourceCNAMERecordUpdate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*terraformClient).QIPClient //nolint:forcetypeassert
+
+	record, err := loadCNAMERecord(d, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if record == nil {
+		return diag.Errorf("could not find a record for id: %s", d.Id())
+	}
+
+	err = checkCNAMERecord(client, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	updatedRecord := *record
+	expanded := expandCNAMERecord(d)
+	updatedRecord.Owner = expanded.Owner
+	updatedRecord.Data1 = expanded.Data1
+
+	err = rr.Update(client, record, &updatedRecord)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(formatV4AddressRRID(&updatedRecord))
+
+	return resourceCNAMERecordRead(ctx, d, meta)
+}
+
+func resourceCNAMERecordDelete(_ context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	record, err := loadCNAMERecord(d, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if record == nil {
+		// Nothing to delete
+		return nil
+	}
+
+	err = rr.Delete(meta.(*terraformClient).QIPClient, record) //nolint:forcetypeassert
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
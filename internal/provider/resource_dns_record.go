@@ -0,0 +1,603 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/dnsname"
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/rr"
+)
+
+var ErrRecordDataMismatch = errors.New("record data does not match the type")
+
+// dnsRecordBlocks maps the record types with a data block to the name of the block.
+var dnsRecordBlocks = map[string]string{
+	rr.RRTypeAAAA: "aaaa",
+	rr.RRTypeMX:   "mx",
+	rr.RRTypeSRV:  "srv",
+	rr.RRTypeTXT:  "txt",
+	rr.RRTypePTR:  "ptr",
+}
+
+// dnsRecordTypes are the record types of qip_dns_record, AAAA records can not be attached to IPv4 objects.
+var dnsRecordTypes = []string{rr.RRTypeMX, rr.RRTypeSRV, rr.RRTypeTXT, rr.RRTypePTR}
+
+func resourceDNSRecord() *schema.Resource {
+	return &schema.Resource{
+		Description: "Managing a MX, SRV, TXT or PTR record attached to an IPv4 address object in QIP. " +
+			"The data is configured in the block matching `type`.",
+
+		CreateContext: resourceDNSRecordCreate,
+		ReadContext:   resourceDNSRecordRead,
+		UpdateContext: resourceDNSRecordUpdate,
+		DeleteContext: resourceDNSRecordDelete,
+
+		CustomizeDiff: checkDNSRecordType(dnsRecordTypes),
+
+		Schema: mergeSchemas(schemaDNSRecordData(dnsRecordTypes), map[string]*schema.Schema{
+			"address": {
+				Description:      "IPv4 address of the object the record is attached to.",
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validateIPV4Address,
+			},
+			"type": schemaDNSRecordType(dnsRecordTypes),
+			"name": {
+				Description: "Owner name of the record, relative to `domain_name`. (e.g. `_ldap._tcp`) " +
+					"Leave empty for a record at the zone apex.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: validateHostname(true),
+				DiffSuppressFunc: suppressNormalizedDiff(normalizeHostname),
+			},
+			"domain_name": {
+				Description:      "DNS Zone of the record.",
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validateDomainName,
+				DiffSuppressFunc: suppressNormalizedDiff(normalizeHostname),
+			},
+			"ttl":        schemaRecordTTL(),
+			"publishing": schemaRecordPublishing(),
+			"fqdn": {
+				Description: "FQDN of the record owner.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		}),
+	}
+}
+
+// schemaDNSRecordData returns the data blocks of the record types.
+func schemaDNSRecordData(recordTypes []string) map[string]*schema.Schema {
+	blocks := map[string]*schema.Schema{
+		"aaaa": {
+			Description: "Data of a `" + rr.RRTypeAAAA + "` record.",
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"address": {
+						Description:      "IPv6 address of the record.",
+						Type:             schema.TypeString,
+						Required:         true,
+						ValidateDiagFunc: validation.ToDiagFunc(validation.IsIPv6Address),
+						DiffSuppressFunc: suppressNormalizedDiff(normalizeIPv6Address),
+					},
+				},
+			},
+		},
+		"mx": {
+			Description: "Data of a `" + rr.RRTypeMX + "` record.",
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"priority": schemaUint16("Priority of the mail exchanger, lower values are preferred."),
+					"host":     schemaRecordHost("Hostname of the mail exchanger."),
+				},
+			},
+		},
+		"srv": {
+			Description: "Data of a `" + rr.RRTypeSRV + "` record.",
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"priority": schemaUint16("Priority of the target, lower values are preferred."),
+					"weight":   schemaUint16("Relative weight of targets with the same priority."),
+					"port":     schemaUint16("Port of the service on the target."),
+					"target":   schemaRecordHost("Hostname providing the service."),
+				},
+			},
+		},
+		"txt": {
+			Description: "Data of a `" + rr.RRTypeTXT + "` record.",
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"value": {
+						Description: "Text of the record, without quotes. Values longer than 255 bytes " +
+							"are split into multiple strings.",
+						Type:     schema.TypeString,
+						Required: true,
+					},
+				},
+			},
+		},
+		"ptr": {
+			Description: "Data of a `" + rr.RRTypePTR + "` record.",
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"target": schemaRecordHost("Hostname the reverse name points to."),
+				},
+			},
+		},
+	}
+
+	schemas := make(map[string]*schema.Schema, len(recordTypes))
+	for _, recordType := range recordTypes {
+		schemas[dnsRecordBlocks[recordType]] = blocks[dnsRecordBlocks[recordType]]
+	}
+
+	return schemas
+}
+
+// normalizeIPv6Address returns the compressed form of an IPv6 address, invalid values are kept.
+func normalizeIPv6Address(value string) string {
+	if addr, err := netip.ParseAddr(normalizeValue(value)); err == nil {
+		return addr.String()
+	}
+
+	return value
+}
+
+// schemaDNSRecordType returns the type attribute for the record types.
+func schemaDNSRecordType(recordTypes []string) *schema.Schema {
+	last := len(recordTypes) - 1
+
+	return &schema.Schema{
+		Description: "Type of the record, one of `" + strings.Join(recordTypes[:last], "`, `") + "` or `" +
+			recordTypes[last] + "`.",
+		Type:             schema.TypeString,
+		Required:         true,
+		ForceNew:         true,
+		ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(recordTypes, false)),
+	}
+}
+
+// mergeSchemas returns a single schema with the attributes of all schemas.
+func mergeSchemas(schemas ...map[string]*schema.Schema) map[string]*schema.Schema {
+	merged := map[string]*schema.Schema{}
+
+	for _, attributes := range schemas {
+		for key, attribute := range attributes {
+			merged[key] = attribute
+		}
+	}
+
+	return merged
+}
+
+func schemaUint16(description string) *schema.Schema {
+	return &schema.Schema{
+		Description:      description,
+		Type:             schema.TypeInt,
+		Required:         true,
+		ValidateDiagFunc: validation.ToDiagFunc(validation.IntBetween(0, 65535)), //nolint:gomnd
+	}
+}
+
+func schemaRecordHost(description string) *schema.Schema {
+	return &schema.Schema{
+		Description:      description,
+		Type:             schema.TypeString,
+		Required:         true,
+		ValidateDiagFunc: validateDomainName,
+		DiffSuppressFunc: suppressNormalizedDiff(normalizeHostname),
+	}
+}
+
+// dnsRecordData is implemented by schema.ResourceData and schema.ResourceDiff.
+type dnsRecordData interface {
+	Get(key string) any
+}
+
+// priorDNSRecordData returns the values of the state, before the changes of the plan are applied.
+// It is used to find the record during an update.
+type priorDNSRecordData struct {
+	d *schema.ResourceData
+}
+
+func (p priorDNSRecordData) Get(key string) any {
+	old, _ := p.d.GetChange(key)
+
+	return old
+}
+
+// checkDNSRecordType returns a CustomizeDiffFunc, that makes sure only the block matching the type is configured.
+func checkDNSRecordType(recordTypes []string) schema.CustomizeDiffFunc {
+	return func(_ context.Context, d *schema.ResourceDiff, _ any) error {
+		if !d.NewValueKnown("type") {
+			return nil
+		}
+
+		recordType := d.Get("type").(string) //nolint:forcetypeassert
+
+		for _, blockType := range recordTypes {
+			block := dnsRecordBlocks[blockType]
+			configured := len(d.Get(block).([]any)) > 0 //nolint:forcetypeassert
+
+			switch {
+			case blockType == recordType && !configured:
+				return fmt.Errorf("%w: %s: block is required for type %s", ErrRecordDataMismatch, block, recordType)
+			case blockType != recordType && configured:
+				return fmt.Errorf("%w: %s: block can not be used for type %s", ErrRecordDataMismatch, block, recordType)
+			}
+		}
+
+		return nil
+	}
+}
+
+// expandDNSRecord returns the record for the configuration.
+//
+//nolint:forcetypeassert
+func expandDNSRecord(d dnsRecordData) (*rr.RR, error) {
+	record, err := expandDNSRecordData(d, rr.ForObject(d.Get("address").(string)),
+		dnsname.Join(d.Get("name").(string), d.Get("domain_name").(string)))
+	if err != nil {
+		return nil, err
+	}
+
+	record.TTL = d.Get("ttl").(int)
+	record.Publishing = d.Get("publishing").(string)
+
+	return record, nil
+}
+
+// expandDNSRecordData returns the record of the infrastructure with the data of the block matching the type.
+//
+//nolint:forcetypeassert
+func expandDNSRecordData(d dnsRecordData, infra rr.Infra, owner string) (*rr.RR, error) {
+	recordType := d.Get("type").(string)
+
+	blocks, _ := d.Get(dnsRecordBlocks[recordType]).([]any)
+	if len(blocks) == 0 || blocks[0] == nil {
+		return nil, fmt.Errorf("%w: %s: block is required for type %s",
+			ErrRecordDataMismatch, dnsRecordBlocks[recordType], recordType)
+	}
+
+	data := blocks[0].(map[string]any)
+
+	switch recordType {
+	case rr.RRTypeAAAA:
+		return rr.NewAAAA(infra, owner, data["address"].(string)), nil
+	case rr.RRTypeMX:
+		return rr.NewMX(infra, owner, rr.MX{
+			Priority: data["priority"].(int),
+			Host:     dnsname.Canonical(data["host"].(string)),
+		}), nil
+	case rr.RRTypeSRV:
+		return rr.NewSRV(infra, owner, rr.SRV{
+			Priority: data["priority"].(int),
+			Weight:   data["weight"].(int),
+			Port:     data["port"].(int),
+			Target:   dnsname.Canonical(data["target"].(string)),
+		}), nil
+	case rr.RRTypeTXT:
+		return rr.NewTXT(infra, owner, data["value"].(string)), nil
+	case rr.RRTypePTR:
+		return rr.NewPTR(infra, owner, dnsname.Canonical(data["target"].(string))), nil
+	}
+
+	return nil, fmt.Errorf("%w: unsupported type %s", ErrRecordDataMismatch, recordType)
+}
+
+// flattenDNSRecordData returns the data block of the record.
+func flattenDNSRecordData(record *rr.RR) (map[string]any, error) {
+	switch record.RRType {
+	case rr.RRTypeAAAA:
+		return map[string]any{"address": normalizeIPv6Address(record.Data1)}, nil
+	case rr.RRTypeMX:
+		mx, err := record.MX()
+		if err != nil {
+			return nil, err //nolint:wrapcheck
+		}
+
+		return map[string]any{"priority": mx.Priority, "host": dnsname.Canonical(mx.Host)}, nil
+	case rr.RRTypeSRV:
+		srv, err := record.SRV()
+		if err != nil {
+			return nil, err //nolint:wrapcheck
+		}
+
+		return map[string]any{
+			"priority": srv.Priority,
+			"weight":   srv.Weight,
+			"port":     srv.Port,
+			"target":   dnsname.Canonical(srv.Target),
+		}, nil
+	case rr.RRTypeTXT:
+		text, err := record.TXT()
+		if err != nil {
+			return nil, err //nolint:wrapcheck
+		}
+
+		return map[string]any{"value": text}, nil
+	case rr.RRTypePTR:
+		return map[string]any{"target": dnsname.Canonical(record.Data1)}, nil
+	}
+
+	return nil, fmt.Errorf("%w: unsupported type %s", ErrRecordDataMismatch, record.RRType)
+}
+
+// flattenDNSRecordBlocks returns the values of the data blocks of the record types, only the block matching
+// the type is set.
+func flattenDNSRecordBlocks(record *rr.RR, recordTypes []string) (map[string]any, error) {
+	data, err := flattenDNSRecordData(record)
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]any{}
+
+	for _, recordType := range recordTypes {
+		block := dnsRecordBlocks[recordType]
+
+		if recordType == record.RRType {
+			values[block] = []any{data}
+		} else {
+			values[block] = []any{}
+		}
+	}
+
+	return values, nil
+}
+
+// loadDNSRecord returns the record of the ID, nil is returned when it is not found.
+//
+// Other records of the owner only differ by their data, which is taken from data: the state, or the prior
+// state during an update. See selectRecord.
+func loadDNSRecord(d *schema.ResourceData, data dnsRecordData, meta any) (*rr.RR, error) {
+	if d.Id() == "" {
+		return nil, ErrIDRequiredToLoad
+	}
+
+	idRecord, err := parseV4AddressRRID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	idRecord = recordWithData(idRecord, data)
+
+	records, err := loadMatchingRR(meta.(*terraformClient).QIPClient, idRecord, idRecord.Equal) //nolint:forcetypeassert
+	if err != nil {
+		return nil, err
+	}
+
+	return selectRecord(records, idRecord)
+}
+
+// recordWithData returns idRecord with the data of the block matching its type, idRecord is returned
+// when data has no such block. (e.g. after an import)
+func recordWithData(idRecord *rr.RR, data dnsRecordData) *rr.RR {
+	record, err := expandDNSRecordData(data, idRecord.Infra(), idRecord.Owner)
+	if err != nil || record.RRType != idRecord.RRType {
+		return idRecord
+	}
+
+	return record
+}
+
+// selectRecord returns the record with the data of idRecord, records only store the owner and type in QIP.
+//
+// When the data was changed outside of Terraform or normalized by QIP, a single record of the owner and type
+// is still the same record. With more candidates it can not be decided, which record belongs to the resource.
+func selectRecord(records []*rr.RR, idRecord *rr.RR) (*rr.RR, error) {
+	var candidates []*rr.RR
+
+	for _, record := range records {
+		if !record.Equal(idRecord) {
+			continue
+		}
+
+		if record.DataEqual(idRecord) {
+			return record, nil
+		}
+
+		candidates = append(candidates, record)
+	}
+
+	switch len(candidates) {
+	case 0:
+		return nil, nil //nolint:nilnil
+	case 1:
+		return candidates[0], nil
+	}
+
+	return nil, fmt.Errorf("%w: %d %s records of %s differ from the state, remove the resource from the state "+
+		"and import the record to manage", ErrNonUniqueRR, len(candidates), idRecord.RRType, idRecord.Owner)
+}
+
+// splitRecordOwner returns the owner relative to domain.
+//
+// An owner outside of domain is split after its first label, so the change shows up in name and domain_name.
+func splitRecordOwner(owner, domain string) (name, zone string) {
+	owner = dnsname.Canonical(owner)
+	domain = dnsname.Canonical(domain)
+
+	switch {
+	case owner == domain:
+		return "", domain
+	case domain != "" && strings.HasSuffix(owner, "."+domain):
+		return dnsname.Relative(owner, domain), domain
+	}
+
+	if first, parent, ok := strings.Cut(owner, "."); ok {
+		return first, parent
+	}
+
+	return owner, ""
+}
+
+func resourceDNSRecordCreate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*terraformClient).QIPClient //nolint:forcetypeassert
+
+	record, err := expandDNSRecord(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = rr.CheckConflict(client, record, nil)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = rr.Create(client, record)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(formatV4AddressRRID(record))
+
+	tflog.Trace(ctx, "Created "+record.RRType+" record "+record.Owner)
+
+	return resourceDNSRecordRead(ctx, d, meta)
+}
+
+func resourceDNSRecordRead(_ context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	record, err := loadDNSRecord(d, d, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if record == nil {
+		// Deleted outside of Terraform, plan to create it again
+		id := d.Id()
+		d.SetId("")
+
+		return diag.Diagnostics{removedRecordWarning(id)}
+	}
+
+	values, err := flattenDNSRecordBlocks(record, dnsRecordTypes)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	name, domain := splitRecordOwner(record.Owner, d.Get("domain_name").(string)) //nolint:forcetypeassert
+
+	values["address"] = record.InfraAddr
+	values["type"] = record.RRType
+	values["name"] = name
+	values["domain_name"] = domain
+	values["ttl"] = record.TTL
+	values["publishing"] = record.Publishing
+	values["fqdn"] = dnsname.Canonical(record.Owner)
+
+	for k, v := range values {
+		err = d.Set(k, v)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return nil
+}
+
+func resourceDNSRecordUpdate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*terraformClient).QIPClient //nolint:forcetypeassert
+
+	record, err := loadDNSRecord(d, priorDNSRecordData{d}, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if record == nil {
+		return diag.Errorf("could not find a record for id: %s", d.Id())
+	}
+
+	expanded, err := expandDNSRecord(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	updatedRecord := *record
+	updatedRecord.Owner = expanded.Owner
+	updatedRecord.Data1 = expanded.Data1
+	updatedRecord.Data2 = expanded.Data2
+	updatedRecord.Data3 = expanded.Data3
+	updatedRecord.Data4 = expanded.Data4
+	updatedRecord.TTL = expanded.TTL
+	updatedRecord.Publishing = expanded.Publishing
+
+	if !dnsname.Equal(record.Owner, updatedRecord.Owner) {
+		err = rr.CheckConflict(client, &updatedRecord, record)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	err = rr.Update(client, record, &updatedRecord)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(formatV4AddressRRID(&updatedRecord))
+
+	return resourceDNSRecordRead(ctx, d, meta)
+}
+
+func resourceDNSRecordDelete(_ context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	record, err := loadDNSRecord(d, d, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if record == nil {
+		// Nothing to delete
+		return nil
+	}
+
+	err = rr.Delete(meta.(*terraformClient).QIPClient, record) //nolint:forcetypeassert
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
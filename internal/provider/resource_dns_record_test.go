@@ -0,0 +1,205 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/rr"
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/test"
+)
+
+func TestExpandDNSRecord(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceDNSRecord().Schema, map[string]any{
+		"address":     "192.0.2.50",
+		"type":        rr.RRTypeSRV,
+		"name":        "_ldap._tcp",
+		"domain_name": "Example.com.",
+		"srv": []any{map[string]any{
+			"priority": 10,
+			"weight":   5,
+			"port":     389,
+			"target":   "LDAP.example.com.",
+		}},
+	})
+
+	record, err := expandDNSRecord(d)
+	require.NoError(t, err)
+	assert.Equal(t, "_ldap._tcp.example.com", record.Owner)
+	assert.Equal(t, []string{"10", "5", "389", "ldap.example.com"},
+		[]string{record.Data1, record.Data2, record.Data3, record.Data4})
+
+	data, err := flattenDNSRecordData(record)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"priority": 10, "weight": 5, "port": 389, "target": "ldap.example.com"}, data)
+
+	// Block of another type
+	d = schema.TestResourceDataRaw(t, resourceDNSRecord().Schema, map[string]any{
+		"address":     "192.0.2.50",
+		"type":        rr.RRTypeMX,
+		"domain_name": "example.com",
+		"ptr":         []any{map[string]any{"target": "host.example.com"}},
+	})
+
+	_, err = expandDNSRecord(d)
+	require.ErrorIs(t, err, ErrRecordDataMismatch)
+}
+
+func TestExpandDNSRecord_LongTXT(t *testing.T) {
+	value := strings.Repeat("x", 300)
+
+	d := schema.TestResourceDataRaw(t, resourceDNSRecord().Schema, map[string]any{
+		"address":     "192.0.2.50",
+		"type":        rr.RRTypeTXT,
+		"domain_name": "example.com",
+		"txt":         []any{map[string]any{"value": value}},
+	})
+
+	record, err := expandDNSRecord(d)
+	require.NoError(t, err)
+	assert.Equal(t, "example.com", record.Owner)
+	assert.Equal(t, rr.QuoteTXT([]string{value[:255], value[255:]}), record.Data1)
+
+	data, err := flattenDNSRecordData(record)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"value": value}, data)
+}
+
+func TestResourceDNSRecordRead(t *testing.T) {
+	c, cleanup := test.GetTestClient(t)
+	defer cleanup()
+
+	url := test.QIPServer + "/api/v1/" + test.QIPOrg + "/rr.json"
+	query := "type=OBJECT&address=192.0.2.50&getDefaultRRs=false"
+	meta := &terraformClient{QIPClient: c}
+
+	newData := func(t *testing.T) *schema.ResourceData {
+		t.Helper()
+
+		d := schema.TestResourceDataRaw(t, resourceDNSRecord().Schema, map[string]any{
+			"address":     "192.0.2.50",
+			"type":        rr.RRTypeTXT,
+			"name":        "txt",
+			"domain_name": "example.com",
+			"txt":         []any{map[string]any{"value": "old"}},
+		})
+
+		d.SetId("192.0.2.50/TXT/txt.example.com")
+
+		return d
+	}
+
+	// The data and owner were changed outside of Terraform, the record is still found
+	httpmock.RegisterResponderWithQuery("GET", url, query,
+		httpmock.NewStringResponder(200, `{"list":[
+			{"owner":"TXT.Example.com","classType":"IN","rrType":"TXT","data1":"\"new\"","ttl":300,"publishing":"INTERNAL",
+				"infraType":"OBJECT","infraAddr":"192.0.2.50"},
+			{"owner":"other.example.com","classType":"IN","rrType":"TXT","data1":"\"other\"","infraType":"OBJECT","infraAddr":"192.0.2.50"}
+		]}`))
+
+	d := newData(t)
+	diags := resourceDNSRecordRead(context.Background(), d, meta)
+	require.Empty(t, diags)
+	assert.Equal(t, "192.0.2.50/TXT/txt.example.com", d.Id())
+	assert.Equal(t, "new", d.Get("txt.0.value"))
+	assert.Equal(t, "txt", d.Get("name"))
+	assert.Equal(t, "example.com", d.Get("domain_name"))
+	assert.Equal(t, 300, d.Get("ttl"))
+	assert.Equal(t, rr.PublishingInternal, d.Get("publishing"))
+
+	// Two candidates can not be told apart
+	httpmock.RegisterResponderWithQuery("GET", url, query,
+		httpmock.NewStringResponder(200, `{"list":[
+			{"owner":"txt.example.com","classType":"IN","rrType":"TXT","data1":"\"new\"","infraType":"OBJECT","infraAddr":"192.0.2.50"},
+			{"owner":"txt.example.com","classType":"IN","rrType":"TXT","data1":"\"newer\"","infraType":"OBJECT","infraAddr":"192.0.2.50"}
+		]}`))
+
+	d = newData(t)
+	diags = resourceDNSRecordRead(context.Background(), d, meta)
+	require.True(t, diags.HasError())
+	assert.Contains(t, diags[0].Summary, ErrNonUniqueRR.Error())
+}
+
+func TestSplitRecordOwner(t *testing.T) {
+	for owner, expected := range map[string][2]string{
+		"example.com":            {"", "example.com"},
+		"_ldap._tcp.Example.com": {"_ldap._tcp", "example.com"},
+		"host.other.org":         {"host", "other.org"},
+	} {
+		name, domain := splitRecordOwner(owner, "example.com.")
+		assert.Equal(t, expected, [2]string{name, domain}, owner)
+	}
+}
+
+func TestAccResourceDNSRecord(t *testing.T) {
+	subnet := getRequiredEnv(t, "QIP_TEST_ACC_RESOURCE_SUBNET")
+	name := getRandomName("terraform-qip-dns")
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+					resource "qip_v4address" "test" {
+						subnet  = "` + subnet + `"
+						name    = "` + name + `"
+					}
+
+					resource "qip_dns_record" "mx" {
+						address     = qip_v4address.test.address
+						type        = "MX"
+						name        = "` + name + `"
+						domain_name = qip_v4address.test.domain_name
+
+						mx {
+							priority = 10
+							host     = "${qip_v4address.test.name}.${qip_v4address.test.domain_name}"
+						}
+					}
+
+					resource "qip_dns_record" "txt" {
+						address     = qip_v4address.test.address
+						type        = "TXT"
+						name        = "` + name + `"
+						domain_name = qip_v4address.test.domain_name
+
+						txt {
+							value = "v=spf1 mx -all"
+						}
+					}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("qip_dns_record.mx", "mx.0.priority", "10"),
+					resource.TestMatchResourceAttr("qip_dns_record.mx", "fqdn", regexp.MustCompile(`^`+regexp.QuoteMeta(name+"."))),
+					resource.TestCheckResourceAttr("qip_dns_record.txt", "txt.0.value", "v=spf1 mx -all"),
+				),
+			},
+		},
+	})
+}
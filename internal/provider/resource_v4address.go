@@ -21,16 +21,32 @@ package provider
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/dnsname"
 	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip"
 	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/v4address"
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/v4subnet"
 )
 
-var ErrIDRequiredToLoad = errors.New("can not load object with empty id")
+var (
+	ErrIDRequiredToLoad = errors.New("can not load object with empty id")
+	ErrMoveSameAddress  = errors.New("the object can not be moved to another subnet with the same address")
+)
+
+const (
+	// selectReleaseAttempts is how often we try to free a selected address after a failed create.
+	selectReleaseAttempts = 3
+)
+
+// selectReleaseBackoff is the initial wait between attempts, it doubles after each failure.
+var selectReleaseBackoff = 2 * time.Second
 
 func resourceV4Address() *schema.Resource {
 	return &schema.Resource{
@@ -41,10 +57,16 @@ func resourceV4Address() *schema.Resource {
 		UpdateContext: resourceV4AddressUpdate,
 		DeleteContext: resourceV4AddressDelete,
 
+		CustomizeDiff: customdiff.All(
+			resourceV4AddressCustomizeDiff,
+			resourceV4AddressCheckDuplicates,
+			customizeDiffServerConstraints("object_class", "domain_name"),
+		),
+
 		Schema: schemaV4Address(false),
 
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: resourceV4AddressImport,
 		},
 	}
 }
@@ -54,15 +76,9 @@ func resourceV4AddressCreate(ctx context.Context, d *schema.ResourceData, meta a
 
 	//nolint:forcetypeassert
 	var (
-		err         error
-		address     = d.Get("address").(string)
-		subnet      = d.Get("subnet").(string)
-		name        = d.Get("name").(string)
-		description = d.Get("description").(string)
-		class       = d.Get("object_class").(string)
-		domain      = d.Get("domain_name").(string)
-		rangeStart  = d.Get("subnet_range_start").(string)
-		rangeEnd    = d.Get("subnet_range_end").(string)
+		err     error
+		address = d.Get("address").(string)
+		subnet  = d.Get("subnet").(string)
 	)
 
 	if subnet == "" {
@@ -72,16 +88,7 @@ func resourceV4AddressCreate(ctx context.Context, d *schema.ResourceData, meta a
 	var addressIsSelected bool
 
 	if address == "" {
-		var addressRange *v4address.SelectedAddrRange
-
-		if rangeStart != "" && rangeEnd != "" {
-			addressRange = &v4address.SelectedAddrRange{
-				StartAddress: rangeStart,
-				EndAddress:   rangeEnd,
-			}
-		}
-
-		selectedAddress, err := v4address.CreateSelected(client.QIPClient, subnet, addressRange)
+		selectedAddress, err := resourceV4AddressSelect(d, client.QIPClient, subnet)
 		if err != nil {
 			return diag.FromErr(err)
 		}
@@ -93,14 +100,13 @@ func resourceV4AddressCreate(ctx context.Context, d *schema.ResourceData, meta a
 	}
 
 	addr := &v4address.V4Address{
-		ObjectAddr:  address,
-		SubnetAddr:  subnet,
-		ObjectName:  name,
-		ObjectClass: class,
-		ObjectDesc:  description,
-		DomainName:  domain,
+		ObjectAddr: address,
+		SubnetAddr: subnet,
 	}
 
+	expandV4Address(d, addr)
+	applyDuplicateCheck(d.Get("duplicate_check").(string), addr) //nolint:forcetypeassert
+
 	if addressIsSelected {
 		err = v4address.Update(client.QIPClient, addr)
 	} else {
@@ -108,7 +114,14 @@ func resourceV4AddressCreate(ctx context.Context, d *schema.ResourceData, meta a
 	}
 
 	if err != nil {
-		return diag.FromErr(err)
+		diags := diag.FromErr(err)
+
+		if addressIsSelected {
+			// Converting the selection failed, free it again so the subnet does not fill up with orphans
+			diags = append(diags, releaseSelectedAddress(ctx, client.QIPClient, address)...)
+		}
+
+		return diags
 	}
 
 	d.SetId(addr.ObjectAddr)
@@ -123,6 +136,93 @@ func resourceV4AddressCreate(ctx context.Context, d *schema.ResourceData, meta a
 	return nil
 }
 
+// resourceV4AddressSelect reserves a free address in the subnet, honoring the allocation settings.
+//
+//nolint:forcetypeassert
+func resourceV4AddressSelect(d *schema.ResourceData, client *qip.Client, subnet string) (string, error) {
+	var (
+		rangeStart = d.Get("subnet_range_start").(string)
+		rangeEnd   = d.Get("subnet_range_end").(string)
+		allocation = d.Get("allocation").([]any)
+	)
+
+	if len(allocation) == 0 || allocation[0] == nil {
+		// Let QIP pick from the whole subnet or the range
+		var addressRange *v4address.SelectedAddrRange
+
+		if rangeStart != "" && rangeEnd != "" {
+			addressRange = &v4address.SelectedAddrRange{
+				StartAddress: rangeStart,
+				EndAddress:   rangeEnd,
+			}
+		}
+
+		return v4address.CreateSelected(client, subnet, addressRange) //nolint:wrapcheck
+	}
+
+	config := allocation[0].(map[string]any)
+
+	alloc := &v4address.Allocation{
+		Strategy:   config["strategy"].(string),
+		Offset:     config["offset"].(int),
+		RangeStart: rangeStart,
+		RangeEnd:   rangeEnd,
+	}
+
+	for _, exclude := range config["exclude"].([]any) {
+		alloc.Exclude = append(alloc.Exclude, exclude.(string))
+	}
+
+	// Candidate ranges are computed from the subnet mask
+	subnetObj, err := v4subnet.Load(client, subnet)
+	if err != nil {
+		return "", fmt.Errorf("could not load subnet for allocation: %w", err)
+	}
+
+	return v4address.SelectWithAllocation(client, subnet, subnetObj.SubnetMask, alloc) //nolint:wrapcheck
+}
+
+// releaseSelectedAddress frees an address selected by CreateSelected, with retries.
+//
+// When the address can not be freed, a warning naming the leaked address is returned.
+func releaseSelectedAddress(ctx context.Context, client *qip.Client, address string) diag.Diagnostics {
+	backoff := selectReleaseBackoff
+
+	for attempt := 1; ; attempt++ {
+		err := v4address.DeleteSelected(client, address)
+		if err == nil {
+			tflog.Trace(ctx, "Released selected V4Address "+address)
+
+			return nil
+		}
+
+		tflog.Warn(ctx, fmt.Sprintf("Releasing selected V4Address %s failed (attempt %d): %s", address, attempt, err))
+
+		if attempt >= selectReleaseAttempts {
+			return leakedSelectionWarning(address, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return leakedSelectionWarning(address, ctx.Err())
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+	}
+}
+
+func leakedSelectionWarning(address string, err error) diag.Diagnostics {
+	return diag.Diagnostics{
+		{
+			Severity: diag.Warning,
+			Summary:  "Selected address " + address + " could not be released",
+			Detail: fmt.Sprintf("The address %s is still selected in QIP and will not be handed out again "+
+				"until the selection is removed manually: %s", address, err),
+		},
+	}
+}
+
 func resourceV4AddressLoad(_ context.Context, d *schema.ResourceData, meta any) (*v4address.V4Address, error) {
 	if d.Id() == "" {
 		return nil, ErrIDRequiredToLoad
@@ -141,6 +241,19 @@ func resourceV4AddressRead(ctx context.Context, d *schema.ResourceData, meta any
 	if err != nil {
 		var notFoundErr *qip.HTTPNotFoundError
 
+		if errors.Is(err, qip.ErrTombstoned) {
+			// Deleted outside of Terraform, plan to create it again
+			d.SetId("")
+
+			return diag.Diagnostics{
+				{
+					Severity: diag.Warning,
+					Summary:  "IPv4 object was deleted in QIP",
+					Detail:   "The object is tombstoned in QIP and will be created again: " + err.Error(),
+				},
+			}
+		}
+
 		if errors.As(err, &notFoundErr) {
 			// Object is not found, so reset Id and return no error
 			d.SetId("")
@@ -152,59 +265,113 @@ func resourceV4AddressRead(ctx context.Context, d *schema.ResourceData, meta any
 	}
 
 	// Update state from object
-	err = d.Set("address", addr.ObjectAddr)
+	err = flattenV4Address(addr, d)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	err = d.Set("subnet", addr.SubnetAddr)
-	if err != nil {
-		return diag.FromErr(err)
+	return nil
+}
+
+//nolint:forcetypeassert
+func resourceV4AddressUpdate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*terraformClient).QIPClient
+
+	if d.HasChanges("address", "subnet") {
+		// An empty address is selected from the new subnet
+		moved, err := v4address.Move(client, d.Id(), d.Get("address").(string), d.Get("subnet").(string))
+		if moved != nil {
+			tflog.Trace(ctx, "Moved V4Address "+d.Id()+" to "+moved.ObjectAddr)
+
+			d.SetId(moved.ObjectAddr)
+		}
+
+		if err != nil {
+			return diag.FromErr(err)
+		}
 	}
 
-	err = d.Set("name", addr.ObjectName)
+	changes := &v4address.V4Address{}
+
+	expandV4Address(d, changes)
+	applyDuplicateCheck(d.Get("duplicate_check").(string), changes)
+
+	// Attributes not managed by Terraform are kept, changes made outside of Terraform since the plan are not overwritten
+	_, err := v4address.Patch(client, d.Id(), expandV4AddressPrior(d), changes,
+		append(v4AddressFieldMask(), duplicateCheckFields...))
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	err = d.Set("description", addr.ObjectDesc)
-	if err != nil {
-		return diag.FromErr(err)
+	return resourceV4AddressRead(ctx, d, meta)
+}
+
+// resourceV4AddressCustomizeDiff marks the address as unknown, when the subnet changes without a configured address,
+// and rejects a subnet change that keeps the configured address.
+func resourceV4AddressCustomizeDiff(_ context.Context, d *schema.ResourceDiff, _ any) error {
+	if d.Id() == "" || !d.HasChange("subnet") {
+		return nil
 	}
 
-	err = d.Set("object_class", addr.ObjectClass)
-	if err != nil {
-		return diag.FromErr(err)
+	if d.GetRawConfig().GetAttr("address").IsNull() {
+		// A new address will be selected in the new subnet during the move
+		return d.SetNewComputed("address") //nolint:wrapcheck
 	}
 
-	err = d.Set("domain_name", addr.DomainName)
-	if err != nil {
-		return diag.FromErr(err)
+	if !d.NewValueKnown("address") {
+		return nil
 	}
 
-	return nil
+	oldSubnet, newSubnet := d.GetChange("subnet")
+	oldAddress, newAddress := d.GetChange("address")
+
+	return checkMoveAddress(oldSubnet.(string), newSubnet.(string), oldAddress.(string), newAddress.(string)) //nolint:forcetypeassert
 }
 
+// checkMoveAddress rejects a move to another subnet that keeps the address of the object.
+func checkMoveAddress(oldSubnet, newSubnet, oldAddress, newAddress string) error {
+	if oldAddress != newAddress {
+		return nil
+	}
+
+	return fmt.Errorf("%w: subnet: %s is in %s, change address to one in %s or remove it to select a free one",
+		ErrMoveSameAddress, newAddress, oldSubnet, newSubnet)
+}
+
+// resourceV4AddressCheckDuplicates searches QIP for other objects using the name during plan.
+//
 //nolint:forcetypeassert
-func resourceV4AddressUpdate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
-	addr, err := resourceV4AddressLoad(ctx, d, meta)
-	if err != nil {
-		return diag.FromErr(err)
+func resourceV4AddressCheckDuplicates(_ context.Context, d *schema.ResourceDiff, meta any) error {
+	mode := d.Get("duplicate_check").(string)
+	if mode == DuplicateCheckNone {
+		return nil
 	}
 
-	// Check if address changed
-	if d.Get("address").(string) != d.Id() {
-		return diag.Errorf("address can not be changed after object was created")
+	if d.Id() != "" && !d.HasChanges("name", "domain_name", "duplicate_check") {
+		return nil
 	}
 
-	addr.ObjectName = d.Get("name").(string)
-	addr.ObjectDesc = d.Get("description").(string)
-	addr.ObjectClass = d.Get("object_class").(string)
-	addr.DomainName = d.Get("domain_name").(string)
+	if !d.NewValueKnown("name") || !d.NewValueKnown("domain_name") {
+		// Values are only known during apply, QIP checks them again when creating the object
+		return nil
+	}
 
-	err = v4address.Update(meta.(*terraformClient).QIPClient, addr)
+	var (
+		client = meta.(*terraformClient).QIPClient
+		domain = d.Get("domain_name").(string)
+		name   = dnsname.Relative(d.Get("name").(string), domain)
+	)
+
+	if mode == DuplicateCheckFQDN && domain != "" {
+		return checkDuplicateFQDN(client, "name", name, domain, d.Id(), nil)
+	}
+
+	addr, err := findDuplicateObject(client, name, "", d.Id())
 	if err != nil {
-		return diag.FromErr(err)
+		return fmt.Errorf("could not check for duplicates: %w", err)
+	} else if addr != nil {
+		return fmt.Errorf("%w: name: hostname %q is already used by the object %s in domain %q",
+			ErrDuplicate, name, addr.ObjectAddr, normalizeValue(addr.DomainName))
 	}
 
 	return nil
@@ -0,0 +1,435 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip"
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/v4address"
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/v4subnet"
+)
+
+const (
+	// blockIndexPlaceholder is replaced with the position of an address in the block.
+	blockIndexPlaceholder = "{index}"
+
+	MaxBlockSize = 256
+)
+
+var ErrInvalidBlockID = errors.New("block id must be in the format first-last")
+
+// blockFieldMask lists the fields managed for each member of a block, see v4address.Patch.
+var blockFieldMask = []string{"objectName", "objectDesc", "objectClass", "domainName"}
+
+func resourceV4AddressBlock() *schema.Resource {
+	return &schema.Resource{
+		Description: "Managing a contiguous block of IPv4 address objects in QIP. One object is created per address.",
+
+		CreateContext: resourceV4AddressBlockCreate,
+		ReadContext:   resourceV4AddressBlockRead,
+		UpdateContext: resourceV4AddressBlockUpdate,
+		DeleteContext: resourceV4AddressBlockDelete,
+
+		CustomizeDiff: customdiff.All(
+			customizeDiffServerConstraints("object_class", "domain_name"),
+			resourceV4AddressBlockCheckMembers,
+		),
+
+		Schema: map[string]*schema.Schema{
+			"subnet": {
+				Description:      "Subnet to find the block of free IPv4 addresses in.",
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validateIPV4Address,
+			},
+			"size": {
+				Description:      "Number of consecutive addresses in the block.",
+				Type:             schema.TypeInt,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntBetween(1, MaxBlockSize)),
+			},
+			"alignment": {
+				Description:      "The first address of the block will be a multiple of this power of two (e.g. `8`).",
+				Type:             schema.TypeInt,
+				Optional:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validatePowerOfTwo,
+			},
+			"subnet_range_start": {
+				Description:      "Starting address of a range to search the block in.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validateIPV4Address,
+			},
+			"subnet_range_end": {
+				Description:      "Ending address of a range to search the block in.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validateIPV4Address,
+			},
+			"name_pattern": {
+				Description: "Hostname pattern for the objects, `" + blockIndexPlaceholder + "` is replaced by the " +
+					"position of the address within the block starting at 1. (e.g. `k8s-node-" + blockIndexPlaceholder + "`)",
+				Type:     schema.TypeString,
+				Required: true,
+				ValidateDiagFunc: validation.AllDiag(
+					validation.ToDiagFunc(validation.StringMatch(
+						regexp.MustCompile(regexp.QuoteMeta(blockIndexPlaceholder)), "must contain "+blockIndexPlaceholder)),
+					validateBlockNamePattern,
+				),
+			},
+			"description": {
+				Description:      "Description for all addresses.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: validateDescription,
+			},
+			"object_class": {
+				Description: "Object class for all addresses. Must be known by the QIP server.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "Virtualized Server",
+			},
+			"domain_name": {
+				Description:      "DNS Zone of the addresses.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ValidateDiagFunc: validateDomainName,
+				DiffSuppressFunc: suppressNormalizedDiff(normalizeHostname),
+			},
+			"addresses": {
+				Description: "List of IPv4 addresses in the block, in ascending order.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"names": {
+				Description: "List of hostnames of the addresses, in the same order as `addresses`. " +
+					"Empty for an object missing in QIP, which is created again by the next apply. " +
+					"An object renamed outside of Terraform is renamed back to the name from `name_pattern`.",
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+// getBlockID uses the first and last address of the block as ID.
+func getBlockID(addresses []string) string {
+	return addresses[0] + "-" + addresses[len(addresses)-1]
+}
+
+// getAddressesFromBlockID returns all addresses of a block from its ID.
+func getAddressesFromBlockID(id string) ([]string, error) {
+	first, last, ok := strings.Cut(id, "-")
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidBlockID, id)
+	}
+
+	addresses, err := v4address.BlockAddresses(first, MaxBlockSize)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidBlockID, err)
+	}
+
+	for i, addr := range addresses {
+		if addr == last {
+			return addresses[:i+1], nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrInvalidBlockID, id)
+}
+
+// blockObjectName returns the name of the address at index (starting at 0) within a block.
+func blockObjectName(pattern string, index int) string {
+	return strings.ReplaceAll(pattern, blockIndexPlaceholder, strconv.Itoa(index+1))
+}
+
+// validateBlockNamePattern validates the hostname of the last possible member, which has the longest index.
+func validateBlockNamePattern(value interface{}, path cty.Path) diag.Diagnostics {
+	pattern, ok := value.(string)
+	if !ok {
+		return diag.Errorf("value is not a string")
+	}
+
+	return validateHostname(false)(blockObjectName(pattern, MaxBlockSize-1), path)
+}
+
+//nolint:forcetypeassert
+func resourceV4AddressBlockCreate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	var (
+		client    = meta.(*terraformClient).QIPClient
+		subnet    = d.Get("subnet").(string)
+		size      = d.Get("size").(int)
+		alignment = d.Get("alignment").(int)
+		pattern   = d.Get("name_pattern").(string)
+	)
+
+	subnetObj, err := v4subnet.Load(client, subnet)
+	if err != nil {
+		return diag.Errorf("could not load subnet: %s", err)
+	}
+
+	alloc := &v4address.Allocation{
+		RangeStart: d.Get("subnet_range_start").(string),
+		RangeEnd:   d.Get("subnet_range_end").(string),
+	}
+
+	addresses, err := v4address.SelectBlock(client, subnet, subnetObj.SubnetMask, size, alignment, alloc)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	tflog.Trace(ctx, "Selected free V4Address block: "+getBlockID(addresses))
+
+	addrs := make([]*v4address.V4Address, 0, len(addresses))
+
+	for i, address := range addresses {
+		addrs = append(addrs, &v4address.V4Address{
+			ObjectAddr:  address,
+			SubnetAddr:  subnet,
+			ObjectName:  blockObjectName(pattern, i),
+			ObjectClass: d.Get("object_class").(string),
+			ObjectDesc:  d.Get("description").(string),
+			DomainName:  d.Get("domain_name").(string),
+		})
+	}
+
+	err = v4address.CreateSelectedBlock(client, addrs)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(getBlockID(addresses))
+
+	tflog.Trace(ctx, "Created V4Address block "+d.Id())
+
+	return resourceV4AddressBlockRead(ctx, d, meta)
+}
+
+// resourceV4AddressBlockLoad loads all objects of the block, missing objects are nil.
+func resourceV4AddressBlockLoad(client *qip.Client, addresses []string) ([]*v4address.V4Address, error) {
+	addrs := make([]*v4address.V4Address, len(addresses))
+
+	for i, address := range addresses {
+		addr, err := v4address.Load(client, address)
+		if err != nil {
+			var notFoundErr *qip.HTTPNotFoundError
+
+			if errors.As(err, &notFoundErr) {
+				continue
+			}
+
+			return nil, err //nolint:wrapcheck
+		}
+
+		addrs[i] = addr
+	}
+
+	return addrs, nil
+}
+
+func resourceV4AddressBlockRead(_ context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*terraformClient).QIPClient //nolint:forcetypeassert
+
+	addresses, err := getAddressesFromBlockID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	addrs, err := resourceV4AddressBlockLoad(client, addresses)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var (
+		diags   diag.Diagnostics
+		names   = make([]string, len(addrs))
+		pattern = d.Get("name_pattern").(string) //nolint:forcetypeassert
+		present *v4address.V4Address
+	)
+
+	for i, addr := range addrs {
+		if addr == nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity:      diag.Warning,
+				Summary:       "Address " + addresses[i] + " of the block is missing in QIP",
+				Detail:        "The object will be created again by the next apply.",
+				AttributePath: cty.GetAttrPath("addresses").IndexInt(i),
+			})
+
+			continue
+		}
+
+		names[i] = normalizeHostname(addr.ObjectName)
+
+		if pattern != "" && !blockMemberNameMatches(names[i], pattern, i) {
+			diags = append(diags, diag.Diagnostic{
+				Severity:      diag.Warning,
+				Summary:       "Address " + addresses[i] + " of the block was renamed to " + names[i] + " in QIP",
+				Detail:        "The object will be renamed to " + blockObjectName(pattern, i) + " again by the next apply.",
+				AttributePath: cty.GetAttrPath("names").IndexInt(i),
+			})
+		}
+
+		if present == nil {
+			present = addr
+		}
+	}
+
+	if present == nil {
+		// None of the objects exists anymore
+		d.SetId("")
+
+		return nil
+	}
+
+	values := map[string]any{
+		"addresses":    addresses,
+		"names":        names,
+		"size":         len(addresses),
+		"description":  normalizeValue(present.ObjectDesc),
+		"object_class": normalizeValue(present.ObjectClass),
+		"domain_name":  normalizeHostname(present.DomainName),
+	}
+
+	for k, v := range values {
+		err = d.Set(k, v)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return diags
+}
+
+// resourceV4AddressBlockCheckMembers plans an update, when Read found members of the block missing or renamed in QIP.
+//
+// Read keeps the address of a missing member, but leaves its name empty.
+func resourceV4AddressBlockCheckMembers(_ context.Context, d *schema.ResourceDiff, _ any) error {
+	if d.Id() == "" {
+		return nil
+	}
+
+	var (
+		names, _   = d.Get("names").([]any)
+		pattern, _ = d.Get("name_pattern").(string)
+	)
+
+	for i, value := range names {
+		name, _ := value.(string)
+		if name == "" || (d.NewValueKnown("name_pattern") && !blockMemberNameMatches(name, pattern, i)) {
+			return d.SetNewComputed("names") //nolint:wrapcheck
+		}
+	}
+
+	return nil
+}
+
+// blockMemberNameMatches checks if the name of the member at index is the one derived from the pattern.
+func blockMemberNameMatches(name, pattern string, index int) bool {
+	return name == normalizeHostname(blockObjectName(pattern, index))
+}
+
+//nolint:forcetypeassert
+func resourceV4AddressBlockUpdate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*terraformClient).QIPClient
+
+	addresses, err := getAddressesFromBlockID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	addrs, err := resourceV4AddressBlockLoad(client, addresses)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	for i, addr := range addrs {
+		changes := &v4address.V4Address{
+			ObjectAddr:  addresses[i],
+			SubnetAddr:  d.Get("subnet").(string),
+			ObjectName:  blockObjectName(d.Get("name_pattern").(string), i),
+			ObjectDesc:  d.Get("description").(string),
+			ObjectClass: d.Get("object_class").(string),
+			DomainName:  d.Get("domain_name").(string),
+		}
+
+		if addr == nil {
+			err = v4address.Create(client, changes)
+		} else {
+			_, err = v4address.Patch(client, addresses[i], nil, changes, blockFieldMask)
+		}
+
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceV4AddressBlockRead(ctx, d, meta)
+}
+
+func resourceV4AddressBlockDelete(_ context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*terraformClient).QIPClient //nolint:forcetypeassert
+
+	addresses, err := getAddressesFromBlockID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var diags diag.Diagnostics
+
+	for _, address := range addresses {
+		err = v4address.Delete(client, address)
+		if err != nil {
+			var notFoundErr *qip.HTTPNotFoundError
+
+			if errors.As(err, &notFoundErr) {
+				continue
+			}
+
+			diags = append(diags, diag.FromErr(err)...)
+		}
+	}
+
+	return diags
+}
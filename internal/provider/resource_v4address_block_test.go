@@ -0,0 +1,171 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/test"
+)
+
+func TestGetAddressesFromBlockID(t *testing.T) {
+	addresses, err := getAddressesFromBlockID("192.0.2.16-192.0.2.19")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"192.0.2.16", "192.0.2.17", "192.0.2.18", "192.0.2.19"}, addresses)
+	assert.Equal(t, "192.0.2.16-192.0.2.19", getBlockID(addresses))
+
+	_, err = getAddressesFromBlockID("192.0.2.16")
+	require.ErrorIs(t, err, ErrInvalidBlockID)
+
+	_, err = getAddressesFromBlockID("192.0.2.16-192.0.2.1")
+	require.ErrorIs(t, err, ErrInvalidBlockID)
+
+	assert.Equal(t, "k8s-node-3", blockObjectName("k8s-node-{index}", 2))
+}
+
+func TestResourceV4AddressBlock_RecreateMissing(t *testing.T) {
+	c, cleanup := test.GetTestClient(t)
+	defer cleanup()
+
+	baseURL := test.QIPServer + "/api/v1/" + test.QIPOrg
+
+	httpmock.RegisterResponder("GET", baseURL+"/v4address/192.0.2.16.json",
+		httpmock.NewStringResponder(200, `{"objectAddr":"192.0.2.16","subnetAddr":"192.0.2.0","objectName":"node-1",
+			"objectClass":"Virtualized Server"}`))
+	httpmock.RegisterResponder("GET", baseURL+"/v4address/192.0.2.17.json",
+		httpmock.NewStringResponder(404, ""))
+
+	var (
+		ctx    = context.Background()
+		meta   = &terraformClient{QIPClient: c}
+		res    = resourceV4AddressBlock()
+		config = map[string]any{
+			"subnet":       "192.0.2.0",
+			"size":         2,
+			"name_pattern": "node-{index}",
+		}
+	)
+
+	d := schema.TestResourceDataRaw(t, res.Schema, config)
+	d.SetId("192.0.2.16-192.0.2.17")
+
+	diags := resourceV4AddressBlockRead(ctx, d, meta)
+	require.Len(t, diags, 1)
+	assert.Equal(t, diag.Warning, diags[0].Severity)
+	assert.Equal(t, "192.0.2.16-192.0.2.17", d.Id())
+	assert.Equal(t, []any{"node-1", ""}, d.Get("names"))
+
+	diff, err := res.Diff(ctx, d.State(), terraform.NewResourceConfigRaw(config), meta)
+	require.NoError(t, err)
+	require.NotNil(t, diff)
+	assert.False(t, diff.RequiresNew())
+	require.Contains(t, diff.Attributes, "names.#")
+	assert.True(t, diff.Attributes["names.#"].NewComputed)
+}
+
+func TestResourceV4AddressBlock_Renamed(t *testing.T) {
+	c, cleanup := test.GetTestClient(t)
+	defer cleanup()
+
+	baseURL := test.QIPServer + "/api/v1/" + test.QIPOrg
+
+	httpmock.RegisterResponder("GET", baseURL+"/v4address/192.0.2.16.json",
+		httpmock.NewStringResponder(200, `{"objectAddr":"192.0.2.16","subnetAddr":"192.0.2.0","objectName":"Node-1",
+			"objectClass":"Virtualized Server","objectDesc":"None","domainName":"None"}`))
+	httpmock.RegisterResponder("GET", baseURL+"/v4address/192.0.2.17.json",
+		httpmock.NewStringResponder(200, `{"objectAddr":"192.0.2.17","subnetAddr":"192.0.2.0","objectName":"other",
+			"objectClass":"Virtualized Server","objectDesc":"None","domainName":"None"}`))
+
+	var (
+		ctx    = context.Background()
+		meta   = &terraformClient{QIPClient: c}
+		res    = resourceV4AddressBlock()
+		config = map[string]any{
+			"subnet":       "192.0.2.0",
+			"size":         2,
+			"name_pattern": "node-{index}",
+		}
+	)
+
+	d := schema.TestResourceDataRaw(t, res.Schema, config)
+	d.SetId("192.0.2.16-192.0.2.17")
+
+	diags := resourceV4AddressBlockRead(ctx, d, meta)
+	require.Len(t, diags, 1)
+	assert.Equal(t, diag.Warning, diags[0].Severity)
+	assert.Contains(t, diags[0].Summary, "renamed to other")
+	assert.Equal(t, []any{"node-1", "other"}, d.Get("names"))
+	assert.Equal(t, "", d.Get("description"))
+	assert.Equal(t, "", d.Get("domain_name"))
+
+	diff, err := res.Diff(ctx, d.State(), terraform.NewResourceConfigRaw(config), meta)
+	require.NoError(t, err)
+	require.NotNil(t, diff)
+	assert.NotContains(t, diff.Attributes, "description")
+	require.Contains(t, diff.Attributes, "names.#")
+	assert.True(t, diff.Attributes["names.#"].NewComputed)
+
+	// Without the renamed member the block has no changes
+	httpmock.RegisterResponder("GET", baseURL+"/v4address/192.0.2.17.json",
+		httpmock.NewStringResponder(200, `{"objectAddr":"192.0.2.17","subnetAddr":"192.0.2.0","objectName":"node-2",
+			"objectClass":"Virtualized Server","objectDesc":"None","domainName":"None"}`))
+
+	assert.Empty(t, resourceV4AddressBlockRead(ctx, d, meta))
+
+	diff, err = res.Diff(ctx, d.State(), terraform.NewResourceConfigRaw(config), meta)
+	require.NoError(t, err)
+	assert.Nil(t, diff)
+}
+
+func TestAccResourceV4AddressBlock(t *testing.T) {
+	subnet := getRequiredEnv(t, "QIP_TEST_ACC_RESOURCE_SUBNET")
+	name := getRandomName("terraform-qip-block")
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+					resource "qip_v4address_block" "test" {
+						subnet       = "` + subnet + `"
+						size         = 4
+						alignment    = 4
+						name_pattern = "` + name + `-{index}"
+					}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("qip_v4address_block.test", "addresses.#", "4"),
+					resource.TestMatchResourceAttr("qip_v4address_block.test", "addresses.0", ipv4AddressRe),
+					resource.TestMatchResourceAttr("qip_v4address_block.test", "names.0", stringRe(name+"-1")),
+					resource.TestMatchResourceAttr("qip_v4address_block.test", "names.3", stringRe(name+"-4")),
+				),
+			},
+		},
+	})
+}
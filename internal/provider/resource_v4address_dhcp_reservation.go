@@ -0,0 +1,238 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip"
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/v4address"
+)
+
+func resourceV4AddressDHCPReservation() *schema.Resource {
+	return &schema.Resource{
+		Description: "Managing a fixed DHCP reservation for an existing IPv4 address object in QIP. " +
+			"The object is turned back into a static object when the reservation is destroyed.",
+
+		CreateContext: resourceV4AddressDHCPReservationCreate,
+		ReadContext:   resourceV4AddressDHCPReservationRead,
+		UpdateContext: resourceV4AddressDHCPReservationUpdate,
+		DeleteContext: resourceV4AddressDHCPReservationDelete,
+
+		Schema: map[string]*schema.Schema{
+			"address": {
+				Description:      "IPv4 address of the object to reserve.",
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validateIPV4Address,
+			},
+			"mac_address": {
+				Description: "MAC address of the client. Separated by colons or hyphens, dotted or plain hex is accepted, " +
+					"it is normalized to lower case separated by colons.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				AtLeastOneOf:     []string{"mac_address", "client_id"},
+				ValidateDiagFunc: validateMACAddress,
+				StateFunc:        stateMACAddress,
+				DiffSuppressFunc: suppressNormalizedDiff(normalizeMACAddress),
+			},
+			"client_id": {
+				Description: "DHCP client identifier of the client.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"dynamic_config": {
+				Description: "Type of the reservation, `" + v4address.DynamicConfigManualDHCP + "` or `" +
+					v4address.DynamicConfigReserved + "`.",
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  v4address.DynamicConfigManualDHCP,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(
+					[]string{v4address.DynamicConfigManualDHCP, v4address.DynamicConfigReserved}, false)),
+			},
+			"dhcp_server": {
+				Description: "DHCP server serving the reservation. Can be changed to move the reservation to another server.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+			},
+			"dhcp_option_template": {
+				Description: "DHCP option template for the reservation.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+			},
+			"dhcp_policy_template": {
+				Description: "DHCP policy template for the reservation.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+			},
+			"lease_time": {
+				Description:      "Lease time in seconds.",
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Computed:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(0)),
+			},
+		},
+
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+	}
+}
+
+// expandV4AddressDHCPReservation applies the reservation from the configuration to the object.
+//
+//nolint:forcetypeassert
+func expandV4AddressDHCPReservation(d *schema.ResourceData, addr *v4address.V4Address) {
+	addr.SetDHCPReservation(
+		d.Get("dynamic_config").(string),
+		normalizeMACAddress(d.Get("mac_address").(string)),
+		d.Get("client_id").(string),
+	)
+
+	addr.DhcpServer = d.Get("dhcp_server").(string)
+	addr.DhcpOptionTemplate = d.Get("dhcp_option_template").(string)
+	addr.DhcpPolicyTemplate = d.Get("dhcp_policy_template").(string)
+
+	if leaseTime := d.Get("lease_time").(int); leaseTime > 0 {
+		addr.LeaseTime = strconv.Itoa(leaseTime)
+	} else {
+		addr.LeaseTime = ""
+	}
+}
+
+func resourceV4AddressDHCPReservationCreate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*terraformClient).QIPClient //nolint:forcetypeassert
+	address := d.Get("address").(string)        //nolint:forcetypeassert
+
+	addr, err := v4address.Load(client, address)
+	if err != nil {
+		return diag.Errorf("could not load IPv4 object to reserve: %s", err)
+	}
+
+	if addr.IsDHCPReservation() {
+		return diag.Errorf("IPv4 object %s is already a DHCP reservation, import it instead", address)
+	}
+
+	changes := &v4address.V4Address{}
+	expandV4AddressDHCPReservation(d, changes)
+
+	addr, err = v4address.Patch(client, address, nil, changes, v4address.DHCPReservationFields)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(addr.ObjectAddr)
+
+	tflog.Trace(ctx, "Created DHCP reservation for V4Address "+d.Id())
+
+	return resourceV4AddressDHCPReservationRead(ctx, d, meta)
+}
+
+func resourceV4AddressDHCPReservationRead(_ context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*terraformClient).QIPClient //nolint:forcetypeassert
+
+	addr, err := v4address.Load(client, d.Id())
+	if err != nil {
+		var notFoundErr *qip.HTTPNotFoundError
+
+		if errors.As(err, &notFoundErr) {
+			d.SetId("")
+
+			return nil
+		}
+
+		return diag.FromErr(err)
+	}
+
+	if !addr.IsDHCPReservation() {
+		// Reservation was removed outside of Terraform
+		d.SetId("")
+
+		return nil
+	}
+
+	leaseTime, _ := strconv.Atoi(normalizeValue(addr.LeaseTime))
+
+	values := map[string]any{
+		"address":              addr.ObjectAddr,
+		"mac_address":          normalizeMACAddress(addr.MacAddr),
+		"client_id":            normalizeValue(addr.ClientId),
+		"dynamic_config":       addr.DynamicConfig,
+		"dhcp_server":          normalizeValue(addr.DhcpServer),
+		"dhcp_option_template": normalizeValue(addr.DhcpOptionTemplate),
+		"dhcp_policy_template": normalizeValue(addr.DhcpPolicyTemplate),
+		"lease_time":           leaseTime,
+	}
+
+	for k, v := range values {
+		err = d.Set(k, v)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return nil
+}
+
+func resourceV4AddressDHCPReservationUpdate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*terraformClient).QIPClient //nolint:forcetypeassert
+
+	changes := &v4address.V4Address{}
+	expandV4AddressDHCPReservation(d, changes)
+
+	_, err := v4address.Patch(client, d.Id(), nil, changes, v4address.DHCPReservationFields)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceV4AddressDHCPReservationRead(ctx, d, meta)
+}
+
+func resourceV4AddressDHCPReservationDelete(_ context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*terraformClient).QIPClient //nolint:forcetypeassert
+
+	changes := &v4address.V4Address{}
+	changes.ClearDHCPReservation()
+
+	_, err := v4address.Patch(client, d.Id(), nil, changes, v4address.DHCPReservationFields)
+	if err != nil {
+		var notFoundErr *qip.HTTPNotFoundError
+
+		if errors.As(err, &notFoundErr) {
+			// Object is gone, nothing to reset
+			return nil
+		}
+
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
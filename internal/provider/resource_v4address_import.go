@@ -0,0 +1,150 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/dnsname"
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip"
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/v4address"
+)
+
+var (
+	ErrFQDNNotFound  = errors.New("no IPv4 object found for FQDN")
+	ErrFQDNAmbiguous = errors.New("more than one IPv4 object found for FQDN")
+	ErrImportSubnet  = errors.New("IPv4 object is not part of the subnet")
+)
+
+// resourceV4AddressImport accepts an address, `subnet/address`, or a FQDN of the object as ID.
+func resourceV4AddressImport(_ context.Context, d *schema.ResourceData, meta any) ([]*schema.ResourceData, error) {
+	client := meta.(*terraformClient).QIPClient //nolint:forcetypeassert
+
+	address, err := resolveV4AddressImportID(client, d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(address)
+
+	// Set the defaults of configuration only attributes, so the first plan is empty
+	defaults := map[string]any{
+		"subnet_range_start": "",
+		"subnet_range_end":   "",
+		"duplicate_check":    DuplicateCheckNone,
+	}
+
+	for k, v := range defaults {
+		err = d.Set(k, v)
+		if err != nil {
+			return nil, fmt.Errorf("could not set %s: %w", k, err)
+		}
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// resolveV4AddressImportID returns the address of the object identified by the import ID.
+func resolveV4AddressImportID(client *qip.Client, id string) (string, error) {
+	id = strings.TrimSpace(id)
+
+	if isIPv4Address(id) {
+		return id, nil
+	}
+
+	if subnet, address, ok := strings.Cut(id, "/"); ok && isIPv4Address(subnet) && isIPv4Address(address) {
+		addr, err := v4address.Load(client, address)
+		if err != nil {
+			return "", fmt.Errorf("could not load IPv4 object %s: %w", address, err)
+		}
+
+		if addr.SubnetAddr != subnet {
+			return "", fmt.Errorf("%w: %s is in subnet %s, not %s", ErrImportSubnet, address, addr.SubnetAddr, subnet)
+		}
+
+		return address, nil
+	}
+
+	if err := dnsname.ValidateHostname(id); err != nil {
+		return "", fmt.Errorf("import ID must be an address, subnet/address or FQDN: %w", err)
+	}
+
+	return findV4AddressByFQDN(client, id)
+}
+
+// findV4AddressByFQDN searches objects for every possible split of the FQDN into name and domain.
+func findV4AddressByFQDN(client *qip.Client, fqdn string) (string, error) {
+	fqdn = dnsname.Canonical(fqdn)
+	labels := strings.Split(fqdn, ".")
+
+	found := make(map[string]bool)
+
+	for i := 1; i <= len(labels); i++ {
+		query := &v4address.Query{
+			ObjectName: strings.Join(labels[:i], "."),
+			DomainName: strings.Join(labels[i:], "."),
+		}
+
+		addrs, err := v4address.List(client, query)
+		if err != nil {
+			var notFoundErr *qip.HTTPNotFoundError
+			if errors.As(err, &notFoundErr) {
+				continue
+			}
+
+			return "", fmt.Errorf("could not search IPv4 objects: %w", err)
+		}
+
+		for _, addr := range addrs {
+			if dnsname.Join(addr.ObjectName, normalizeValue(addr.DomainName)) == fqdn {
+				found[addr.ObjectAddr] = true
+			}
+		}
+	}
+
+	addresses := make([]string, 0, len(found))
+	for address := range found {
+		addresses = append(addresses, address)
+	}
+
+	sort.Strings(addresses)
+
+	switch len(addresses) {
+	case 0:
+		return "", fmt.Errorf("%w: %s", ErrFQDNNotFound, fqdn)
+	case 1:
+		return addresses[0], nil
+	default:
+		return "", fmt.Errorf("%w: %s is used by %s, import by address instead",
+			ErrFQDNAmbiguous, fqdn, strings.Join(addresses, ", "))
+	}
+}
+
+func isIPv4Address(value string) bool {
+	ip := net.ParseIP(value)
+
+	return ip != nil && ip.To4() != nil
+}
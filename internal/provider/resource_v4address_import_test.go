@@ -0,0 +1,72 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/test"
+)
+
+func TestResolveV4AddressImportID(t *testing.T) {
+	c, cleanup := test.GetTestClient(t)
+	defer cleanup()
+
+	baseURL := test.QIPServer + "/api/v1/" + test.QIPOrg
+
+	httpmock.RegisterResponder("GET", baseURL+"/v4address/192.0.2.10.json",
+		httpmock.NewStringResponder(200, `{"objectAddr":"192.0.2.10","subnetAddr":"192.0.2.0","objectName":"host"}`))
+	httpmock.RegisterResponderWithQuery("GET", baseURL+"/v4address.json", "name=host&domainName=corp.example.com",
+		httpmock.NewStringResponder(200, `{"list":[
+			{"objectAddr":"192.0.2.10","objectName":"host","domainName":"corp.example.com"}]}`))
+	httpmock.RegisterResponderWithQuery("GET", baseURL+"/v4address.json", "name=twin&domainName=corp.example.com",
+		httpmock.NewStringResponder(200, `{"list":[
+			{"objectAddr":"192.0.2.20","objectName":"twin","domainName":"corp.example.com"},
+			{"objectAddr":"192.0.2.21","objectName":"twin","domainName":"corp.example.com"}]}`))
+	httpmock.RegisterNoResponder(httpmock.NewStringResponder(404, ""))
+
+	address, err := resolveV4AddressImportID(c, "192.0.2.10")
+	require.NoError(t, err)
+	assert.Equal(t, "192.0.2.10", address)
+
+	address, err = resolveV4AddressImportID(c, "192.0.2.0/192.0.2.10")
+	require.NoError(t, err)
+	assert.Equal(t, "192.0.2.10", address)
+
+	_, err = resolveV4AddressImportID(c, "198.51.100.0/192.0.2.10")
+	require.ErrorIs(t, err, ErrImportSubnet)
+
+	address, err = resolveV4AddressImportID(c, "Host.corp.example.com.")
+	require.NoError(t, err)
+	assert.Equal(t, "192.0.2.10", address)
+
+	_, err = resolveV4AddressImportID(c, "twin.corp.example.com")
+	require.ErrorIs(t, err, ErrFQDNAmbiguous)
+	assert.Contains(t, err.Error(), "192.0.2.20, 192.0.2.21")
+
+	_, err = resolveV4AddressImportID(c, "missing.corp.example.com")
+	require.ErrorIs(t, err, ErrFQDNNotFound)
+
+	_, err = resolveV4AddressImportID(c, "not a name")
+	require.Error(t, err)
+}
@@ -24,61 +24,169 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/dnsname"
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip"
 	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/rr"
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/v4address"
 )
 
-var ErrNonUniqueRR = errors.New("non unique RR found")
+// v4AddressRRIDParts is the number of parts of a qip_v4address_rr ID (address/TYPE/owner).
+const v4AddressRRIDParts = 3
+
+var (
+	ErrNonUniqueRR   = errors.New("non unique RR found")
+	ErrRRNotFound    = errors.New("RR not found")
+	ErrV4AddressRRID = errors.New("ID must be address/TYPE/owner")
+)
 
 func resourceV4AddressRR() *schema.Resource {
 	return &schema.Resource{
-		Description: "Managing additional RR for IPv4 address objects in QIP. Only supports A records as of now.",
+		Description: "Managing additional RR for IPv4 address objects in QIP. Only supports A records, " +
+			"see `qip_cname_record` for CNAME records.",
 
 		CreateContext: resourceV4AddressRRCreate,
 		ReadContext:   resourceV4AddressRRRead,
 		UpdateContext: resourceV4AddressRRUpdate,
 		DeleteContext: resourceV4AddressRRDelete,
 
+		CustomizeDiff: customdiff.All(
+			resourceV4AddressRRCheckDuplicates,
+			resourceV4AddressRRRemoveDuplicates,
+			customdiff.ComputedIf("ptr_created", func(_ context.Context, d *schema.ResourceDiff, _ any) bool {
+				return d.HasChanges("address", "name", "domain_name", "create_ptr")
+			}),
+			customizeDiffServerConstraints("domain_name"),
+		),
+
 		Schema: map[string]*schema.Schema{
 			"address": {
-				Description: "IPv4 address to attach a RR to.",
+				Description: "IPv4 address to attach a RR to. When the address object was moved, the RR is moved as well.",
 				Type:        schema.TypeString,
 				Required:    true,
-				ForceNew:    true,
 			},
 			"name": {
-				Description: "Hostname for the address. (e.g. `entry-extra` or `*.entry-extra`)",
-				Type:        schema.TypeString,
-				Required:    true,
+				Description: "Hostname for the address. (e.g. `entry-extra` or `*.entry-extra`) " +
+					"Names already qualified with `domain_name` are not extended again.",
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validateHostname(true),
+				DiffSuppressFunc: suppressNormalizedDiff(normalizeHostname),
 			},
 			"domain_name": {
-				Description: "DNS Zone for the additional RR.",
-				Type:        schema.TypeString,
-				Required:    true,
+				Description:      "DNS Zone for the additional RR.",
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validateDomainName,
+				DiffSuppressFunc: suppressNormalizedDiff(normalizeHostname),
+			},
+			"ttl":              schemaRecordTTL(),
+			"publishing":       schemaRecordPublishing(),
+			"duplicate_check":  schemaDuplicateCheck(DuplicateCheckFQDN),
+			"duplicate_policy": schemaDuplicatePolicy(),
+			"duplicates": {
+				Description: "Number of duplicate records found in QIP, which are not managed by this resource.",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"create_ptr": {
+				Description: "Let QIP create the matching PTR record in the reverse zone of the address. " +
+					"The PTR is deleted together with the record, or when this is disabled. " +
+					"PTR records, that already existed before, are kept. (e.g. managed by `qip_zone_record`)",
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"ptr_created": {
+				Description: "Whether QIP created a PTR record for `create_ptr`, which is deleted by this resource.",
+				Type:        schema.TypeBool,
+				Computed:    true,
 			},
 		},
 
-		// Importer: &schema.ResourceImporter{
-		// 	StateContext: schema.ImportStatePassthroughContext,
-		// },
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceV4AddressRRImport,
+		},
+
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourceV4AddressRRResourceV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceV4AddressRRStateUpgradeV0,
+			},
+		},
+	}
+}
+
+// resourceV4AddressRRResourceV0 is the schema of version 0, which used a base64 encoded record as ID.
+func resourceV4AddressRRResourceV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"address":         {Type: schema.TypeString, Required: true},
+			"name":            {Type: schema.TypeString, Required: true},
+			"domain_name":     {Type: schema.TypeString, Required: true},
+			"duplicate_check": {Type: schema.TypeString, Optional: true},
+		},
 	}
 }
 
-// getIDFromRR uses the base64 encoded JSON representation of a record as ID.
-func getIDFromRR(record *rr.RR) (string, error) {
-	data, err := json.Marshal(record)
+// resourceV4AddressRRStateUpgradeV0 converts the base64 encoded ID of version 0 to the readable format,
+// see formatV4AddressRRID.
+func resourceV4AddressRRStateUpgradeV0(_ context.Context, rawState map[string]any, _ any) (map[string]any, error) {
+	return upgradeRRIDV0(rawState, "qip_v4address_rr", formatV4AddressRRID, parseV4AddressRRID)
+}
+
+// upgradeRRIDV0 converts the base64 encoded ID of version 0 (see getRRFromID) to the readable format of formatID.
+//
+// IDs already accepted by parseID are kept.
+func upgradeRRIDV0(rawState map[string]any, resourceName string, formatID func(record *rr.RR) string,
+	parseID func(id string) (*rr.RR, error),
+) (map[string]any, error) {
+	id, _ := rawState["id"].(string)
+	if id == "" {
+		return rawState, nil
+	}
+
+	record, err := getRRFromID(id)
 	if err != nil {
-		return "", fmt.Errorf("could not encode JSON: %w", err)
+		if _, parseErr := parseID(id); parseErr == nil {
+			// Already converted
+			return rawState, nil
+		}
+
+		return nil, fmt.Errorf("could not upgrade ID of %s: %w", resourceName, err)
+	}
+
+	rawState["id"] = formatID(record)
+
+	return rawState, nil
+}
+
+// formatV4AddressRRID returns the readable ID of a record of an object, e.g. 192.0.2.50/A/www.example.com.
+func formatV4AddressRRID(record *rr.RR) string {
+	return record.InfraAddr + "/" + record.RRType + "/" + dnsname.Canonical(record.Owner)
+}
+
+// parseV4AddressRRID returns the identifying attributes of a record from an ID, see formatV4AddressRRID.
+func parseV4AddressRRID(id string) (*rr.RR, error) {
+	parts := strings.SplitN(id, "/", v4AddressRRIDParts)
+	if len(parts) != v4AddressRRIDParts || !isIPv4Address(parts[0]) || parts[1] == "" || parts[2] == "" {
+		return nil, fmt.Errorf("%w: %s", ErrV4AddressRRID, id)
 	}
 
-	return base64.StdEncoding.EncodeToString(data), nil
+	return rr.NewForObject(dnsname.Canonical(parts[2]), strings.ToUpper(parts[1]), parts[0]), nil
 }
 
 // getRRFromID returns a rr.RR decoded from the ID, which is a base64 encoded JSON representation of a record.
+//
+// It is only used to upgrade the IDs of version 0 of the record resources.
 func getRRFromID(id string) (*rr.RR, error) {
 	data, err := base64.StdEncoding.DecodeString(id)
 	if err != nil {
@@ -95,131 +203,403 @@ func getRRFromID(id string) (*rr.RR, error) {
 	return &record, nil
 }
 
+// resourceV4AddressRRCheckDuplicates searches QIP for other objects or records using the FQDN during plan.
+//
+//nolint:forcetypeassert
+func resourceV4AddressRRCheckDuplicates(_ context.Context, d *schema.ResourceDiff, meta any) error {
+	if d.Get("duplicate_check").(string) == DuplicateCheckNone {
+		return nil
+	}
+
+	if d.Id() != "" && !d.HasChanges("name", "domain_name", "duplicate_check") {
+		return nil
+	}
+
+	if !d.NewValueKnown("name") || !d.NewValueKnown("domain_name") {
+		return nil
+	}
+
+	var ownRecord *rr.RR
+
+	if d.Id() != "" {
+		var err error
+
+		ownRecord, err = parseV4AddressRRID(d.Id())
+		if err != nil {
+			return err
+		}
+	}
+
+	return checkDuplicateFQDN(meta.(*terraformClient).QIPClient, "name",
+		d.Get("name").(string), d.Get("domain_name").(string), "", ownRecord)
+}
+
+// resourceV4AddressRRRemoveDuplicates plans an update to delete the duplicates found by the last read,
+// when duplicate_policy is remove_extras.
+//
+//nolint:forcetypeassert
+func resourceV4AddressRRRemoveDuplicates(_ context.Context, d *schema.ResourceDiff, _ any) error {
+	if d.Id() == "" || d.Get("duplicate_policy").(string) != DuplicatePolicyRemoveExtras ||
+		d.Get("duplicates").(int) == 0 {
+		return nil
+	}
+
+	return d.SetNew("duplicates", 0) //nolint:wrapcheck
+}
+
 func resourceV4AddressRRCreate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
 	//nolint:forcetypeassert
 	var (
-		err     error
-		client  = meta.(*terraformClient).QIPClient
-		address = d.Get("address").(string)
-		name    = d.Get("name").(string)
-		domain  = d.Get("domain_name").(string)
+		err        error
+		client     = meta.(*terraformClient).QIPClient
+		address    = d.Get("address").(string)
+		name       = d.Get("name").(string)
+		domain     = d.Get("domain_name").(string)
+		ttl        = d.Get("ttl").(int)
+		publishing = d.Get("publishing").(string)
+		createPTR  = d.Get("create_ptr").(bool)
 	)
 
-	fqdn := name + "." + domain
+	fqdn := dnsname.Join(name, domain)
 
 	record := rr.NewAForObject(fqdn, address)
+	record.TTL = ttl
+	record.Publishing = publishing
+	record.IsCreatingReverseZoneRR = createPTR
 
-	err = rr.Create(client, record)
+	err = rr.CheckConflict(client, record, nil)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	id, err := getIDFromRR(record)
+	ptrCreated, err := createsReversePTR(client, record, func() error {
+		return rr.Create(client, record)
+	})
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	d.SetId(id)
+	d.SetId(formatV4AddressRRID(record))
+
+	err = d.Set("ptr_created", ptrCreated)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	tflog.Trace(ctx, "Created RR for V4Address "+d.Id())
 
 	return nil
 }
 
-func resourceV4AddressRRLoad(_ context.Context, d *schema.ResourceData, meta any) (*rr.RR, error) {
+// createsReversePTR runs apply, which creates or updates record, and checks whether QIP created a PTR record
+// for it. PTR records, that existed before, do not belong to the record.
+func createsReversePTR(client *qip.Client, record *rr.RR, apply func() error) (bool, error) {
+	if !record.IsCreatingReverseZoneRR {
+		return false, apply()
+	}
+
+	before, err := rr.LoadReversePTR(client, record)
+	if err != nil {
+		return false, fmt.Errorf("could not load PTR records: %w", err)
+	}
+
+	err = apply()
+	if err != nil {
+		return false, err
+	}
+
+	after, err := rr.LoadReversePTR(client, record)
+	if err != nil {
+		return false, fmt.Errorf("could not load PTR records: %w", err)
+	}
+
+	return len(after) > len(before), nil
+}
+
+// resourceV4AddressRRLoad returns the record of the ID and its duplicates, which are handled by the
+// duplicate_policy, see selectDuplicate.
+//
+// Nil is returned when the record is not found.
+func resourceV4AddressRRLoad(_ context.Context, d *schema.ResourceData, meta any) (*rr.RR, []*rr.RR, diag.Diagnostics) {
 	if d.Id() == "" {
-		return nil, ErrIDRequiredToLoad
+		return nil, nil, diag.FromErr(ErrIDRequiredToLoad)
+	}
+
+	idRecord, err := parseV4AddressRRID(d.Id())
+	if err != nil {
+		return nil, nil, diag.FromErr(err)
 	}
 
-	idRecord, err := getRRFromID(d.Id())
+	records, err := loadMatchingRR(meta.(*terraformClient).QIPClient, idRecord, idRecord.Equal) //nolint:forcetypeassert
+	if err != nil {
+		return nil, nil, diag.FromErr(err)
+	}
+
+	return selectDuplicate(records, d.Get("duplicate_policy").(string)) //nolint:forcetypeassert
+}
+
+// loadSingleRR searches the records of the object for the record, nil is returned when it is not found.
+func loadSingleRR(client *qip.Client, idRecord *rr.RR) (*rr.RR, error) {
+	return loadSingleRRMatching(client, idRecord, idRecord.Equal)
+}
+
+// loadSingleRRMatching searches the records of the object for a single record accepted by match.
+func loadSingleRRMatching(client *qip.Client, idRecord *rr.RR, match func(record *rr.RR) bool) (*rr.RR, error) {
+	records, err := loadMatchingRR(client, idRecord, match)
 	if err != nil {
 		return nil, err
 	}
 
-	records, err := rr.LoadAllForObject(meta.(*terraformClient).QIPClient, idRecord.InfraAddr)
+	switch len(records) {
+	case 0:
+		return nil, nil //nolint:nilnil
+	case 1:
+		return records[0], nil
+	}
+
+	return nil, ErrNonUniqueRR
+}
+
+// loadMatchingRR returns all records of the object accepted by match.
+//
+// No records are returned when the object does not exist anymore.
+func loadMatchingRR(client *qip.Client, idRecord *rr.RR, match func(record *rr.RR) bool) ([]*rr.RR, error) {
+	records, err := rr.LoadAllForObject(client, idRecord.InfraAddr)
 	if err != nil {
+		var notFoundErr *qip.HTTPNotFoundError
+		if errors.As(err, &notFoundErr) {
+			return nil, nil
+		}
+
 		return nil, err //nolint:wrapcheck
 	}
 
-	var singleRecord *rr.RR
+	var matching []*rr.RR
 
-	// Search for a single record
 	for _, record := range records {
-		if record.Equal(idRecord) {
-			if singleRecord == nil {
-				singleRecord = record
-			} else {
-				return nil, ErrNonUniqueRR
-			}
+		if match(record) {
+			matching = append(matching, record)
 		}
 	}
 
-	return singleRecord, nil
+	return matching, nil
 }
 
 func resourceV4AddressRRRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
-	record, err := resourceV4AddressRRLoad(ctx, d, meta)
-	if err != nil {
-		return diag.FromErr(err)
+	record, duplicates, diags := resourceV4AddressRRLoad(ctx, d, meta)
+	if diags.HasError() {
+		return diags
 	}
 
 	if record == nil {
-		return diag.Errorf("could not find a record for id: %s", d.Id())
+		// Deleted outside of Terraform, plan to create it again
+		id := d.Id()
+		d.SetId("")
+
+		return append(diags, removedRecordWarning(id))
 	}
 
-	// Currently we have no state to refresh, all attributes are identifying
+	values := map[string]any{
+		"ttl":        record.TTL,
+		"publishing": record.Publishing,
+		"duplicates": len(duplicates),
+	}
 
-	return nil
+	for k, v := range values {
+		err := d.Set(k, v)
+		if err != nil {
+			return append(diags, diag.FromErr(err)...)
+		}
+	}
+
+	return diags
+}
+
+// removedRecordWarning informs that a record is removed from the state, since it was not found in QIP.
+func removedRecordWarning(id string) diag.Diagnostic {
+	return diag.Diagnostic{
+		Severity: diag.Warning,
+		Summary:  "RR was deleted in QIP",
+		Detail:   "The record " + id + " was not found in QIP and will be created again.",
+	}
 }
 
 //nolint:forcetypeassert
 func resourceV4AddressRRUpdate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
-	record, err := resourceV4AddressRRLoad(ctx, d, meta)
-	if err != nil {
-		return diag.FromErr(err)
+	client := meta.(*terraformClient).QIPClient
+
+	record, duplicates, diags := resourceV4AddressRRLoad(ctx, d, meta)
+	if diags.HasError() {
+		return diags
+	}
+
+	address := d.Get("address").(string)
+
+	if record == nil && d.HasChange("address") {
+		// The address object might have been moved together with its records
+		movedRecord, err := parseV4AddressRRID(d.Id())
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		movedRecord.InfraAddr = address
+		movedRecord.Data1 = address
+
+		records, err := loadMatchingRR(client, movedRecord, movedRecord.Equal)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		record, duplicates, diags = selectDuplicate(records, d.Get("duplicate_policy").(string))
+		if diags.HasError() {
+			return diags
+		}
 	}
 
 	if record == nil {
-		return diag.Errorf("could not find a record for id: %s", d.Id())
+		return append(diags, diag.Errorf("could not find a record for id: %s", d.Id())...)
 	}
 
-	updatedRecord := *record
+	if d.Get("duplicate_policy").(string) == DuplicatePolicyRemoveExtras {
+		err := removeDuplicates(client, duplicates)
+		if err != nil {
+			return append(diags, diag.FromErr(err)...)
+		}
 
-	// Only allow to change the record owner (FQDN as of now)
-	updatedRecord.Owner = d.Get("name").(string) + "." + d.Get("domain_name").(string)
+		duplicates = nil
+	}
 
-	err = rr.Update(meta.(*terraformClient).QIPClient, record, &updatedRecord)
+	err := d.Set("duplicates", len(duplicates))
 	if err != nil {
-		return diag.FromErr(err)
+		return append(diags, diag.FromErr(err)...)
 	}
 
-	// update ID after the change - because some values are identifying
-	id, err := getIDFromRR(&updatedRecord)
+	updatedRecord := *record
+
+	// Only allow to change the record owner (FQDN as of now), the address, TTL and publishing
+	updatedRecord.Owner = dnsname.Join(d.Get("name").(string), d.Get("domain_name").(string))
+	updatedRecord.InfraAddr = address
+	updatedRecord.Data1 = address
+	updatedRecord.TTL = d.Get("ttl").(int)
+	updatedRecord.Publishing = d.Get("publishing").(string)
+	updatedRecord.IsCreatingReverseZoneRR = d.Get("create_ptr").(bool)
+
+	oldCreatePTR, _ := d.GetChange("create_ptr")
+	oldPTRCreated, _ := d.GetChange("ptr_created")
+	identityChanged := !updatedRecord.Equal(record) || updatedRecord.Data1 != record.Data1
+	ptrCreated := oldCreatePTR.(bool) && oldPTRCreated.(bool)
+
+	if ptrCreated && (identityChanged || !updatedRecord.IsCreatingReverseZoneRR) {
+		// The PTR points back to the old owner, QIP creates a new one for the updated record
+		err = rr.DeleteReversePTR(client, record)
+		if err != nil {
+			return append(diags, diag.FromErr(err)...)
+		}
+
+		ptrCreated = false
+	}
+
+	if identityChanged || d.HasChange("create_ptr") {
+		ptrCreated, err = createsReversePTR(client, &updatedRecord, func() error {
+			return rr.Update(client, record, &updatedRecord)
+		})
+		if err != nil {
+			return append(diags, diag.FromErr(err)...)
+		}
+	} else if updatedRecord.TTL != record.TTL || updatedRecord.Publishing != record.Publishing {
+		err = rr.Update(client, record, &updatedRecord)
+		if err != nil {
+			return append(diags, diag.FromErr(err)...)
+		}
+	}
+
+	err = d.Set("ptr_created", ptrCreated)
 	if err != nil {
-		return diag.FromErr(err)
+		return append(diags, diag.FromErr(err)...)
 	}
 
-	d.SetId(id)
+	// update ID after the change - because some values are identifying
+	d.SetId(formatV4AddressRRID(&updatedRecord))
 
-	return nil
+	return diags
 }
 
 //nolint:forcetypeassert
 func resourceV4AddressRRDelete(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
-	record, err := resourceV4AddressRRLoad(ctx, d, meta)
-	if err != nil {
-		return diag.FromErr(err)
+	record, _, diags := resourceV4AddressRRLoad(ctx, d, meta)
+	if diags.HasError() {
+		return diags
 	}
 
-	if d.Id() == "" || record == nil {
+	if record == nil {
 		// Nothing to delete
-		return nil
+		return diags
 	}
 
-	err = rr.Delete(meta.(*terraformClient).QIPClient, record)
+	client := meta.(*terraformClient).QIPClient
+
+	err := rr.Delete(client, record)
 	if err != nil {
-		return diag.FromErr(err)
+		return append(diags, diag.FromErr(err)...)
 	}
 
-	return nil
+	if d.Get("create_ptr").(bool) && d.Get("ptr_created").(bool) {
+		err = rr.DeleteReversePTR(client, record)
+		if err != nil {
+			return append(diags, diag.FromErr(err)...)
+		}
+	}
+
+	return diags
+}
+
+// resourceV4AddressRRImport imports a record by its ID address/TYPE/owner.
+//
+// The domain of the object is used as domain_name when the owner belongs to it, otherwise the parent domain
+// of the owner.
+func resourceV4AddressRRImport(_ context.Context, d *schema.ResourceData, meta any) ([]*schema.ResourceData, error) {
+	client := meta.(*terraformClient).QIPClient //nolint:forcetypeassert
+
+	idRecord, err := parseV4AddressRRID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	record, err := loadSingleRR(client, idRecord)
+	if err != nil {
+		return nil, err
+	} else if record == nil {
+		return nil, fmt.Errorf("%w: %s", ErrRRNotFound, d.Id())
+	}
+
+	owner := dnsname.Canonical(record.Owner)
+	domain := ""
+
+	addr, err := v4address.Load(client, record.InfraAddr)
+	if err == nil && strings.HasSuffix(owner, "."+dnsname.Canonical(addr.DomainName)) {
+		domain = dnsname.Canonical(addr.DomainName)
+	} else if _, parent, ok := strings.Cut(owner, "."); ok {
+		domain = parent
+	}
+
+	values := map[string]any{
+		"address":          record.InfraAddr,
+		"name":             dnsname.Relative(owner, domain),
+		"domain_name":      domain,
+		"duplicate_check":  DuplicateCheckNone,
+		"duplicate_policy": DuplicatePolicyError,
+		"create_ptr":       false,
+		"ptr_created":      false,
+	}
+
+	for k, v := range values {
+		err = d.Set(k, v)
+		if err != nil {
+			return nil, fmt.Errorf("could not set %s: %w", k, err)
+		}
+	}
+
+	d.SetId(formatV4AddressRRID(record))
+
+	return []*schema.ResourceData{d}, nil
 }
@@ -0,0 +1,448 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/dnsname"
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip"
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/rr"
+)
+
+var (
+	ErrRRSetValue          = errors.New("invalid record value")
+	ErrRRSetDuplicate      = errors.New("record is configured more than once")
+	ErrRRSetObjectNotFound = errors.New("IPv4 object not found")
+	ErrRRSetImportID       = errors.New("import ID must be an IPv4 address")
+)
+
+// rrSetTypes are the record types supported by qip_v4address_rr_set.
+var rrSetTypes = []string{rr.RRTypeA, rr.RRTypeCNAME, rr.RRTypeMX, rr.RRTypePTR, rr.RRTypeSRV, rr.RRTypeTXT}
+
+func resourceV4AddressRRSet() *schema.Resource {
+	return &schema.Resource{
+		Description: "Managing the complete set of additional RRs of an IPv4 address object in QIP. " +
+			"Records of the object not configured here, including those added outside of Terraform, are deleted. " +
+			"The default records QIP generates for the object itself are not affected. " +
+			"Do not combine with `qip_v4address_rr`, `qip_cname_record` or `qip_dns_record` for the same address.",
+
+		CreateContext: resourceV4AddressRRSetCreate,
+		ReadContext:   resourceV4AddressRRSetRead,
+		UpdateContext: resourceV4AddressRRSetUpdate,
+		DeleteContext: resourceV4AddressRRSetDelete,
+
+		CustomizeDiff: resourceV4AddressRRSetCheckRecords,
+
+		Schema: map[string]*schema.Schema{
+			"address": {
+				Description:      "IPv4 address of the object owning the records.",
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validateIPV4Address,
+			},
+			"record": {
+				Description: "Record of the object, all records of the object not listed are deleted.",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Set:         hashV4AddressRRSetRecord,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"owner": {
+							Description:      "FQDN of the record owner. (e.g. `www.example.com`)",
+							Type:             schema.TypeString,
+							Required:         true,
+							ValidateDiagFunc: validateHostname(true),
+							DiffSuppressFunc: suppressNormalizedDiff(normalizeHostname),
+						},
+						"type": {
+							Description: "Type of the record, one of `" + strings.Join(rrSetTypes, "`, `") + "`.",
+							Type:        schema.TypeString,
+							Required:    true,
+							ValidateDiagFunc: validation.ToDiagFunc(
+								validation.StringInSlice(rrSetTypes, false)),
+						},
+						"value": {
+							Description: "Data of the record as in a zone file, without quoting for `TXT`. " +
+								"(e.g. the address of the object for `A`, `10 mail.example.com` for `MX` " +
+								"or `0 5 389 dc.example.com` for `SRV`)",
+							Type:             schema.TypeString,
+							Required:         true,
+							DiffSuppressFunc: suppressV4AddressRRSetValueDiff,
+						},
+						"ttl":        schemaRecordTTL(),
+						"publishing": schemaRecordPublishing(),
+					},
+				},
+			},
+		},
+
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceV4AddressRRSetImport,
+		},
+	}
+}
+
+// expandV4AddressRRSetRecord returns the record of a record block for the object.
+//
+//nolint:forcetypeassert
+func expandV4AddressRRSetRecord(address string, values map[string]any) (*rr.RR, error) {
+	var (
+		owner      = dnsname.Canonical(values["owner"].(string))
+		recordType = values["type"].(string)
+		value      = values["value"].(string)
+		record     *rr.RR
+		err        error
+	)
+
+	switch recordType {
+	case rr.RRTypeA:
+		if value != address {
+			return nil, fmt.Errorf("%w: %s: A records must point to the address of the object %s",
+				ErrRRSetValue, owner, address)
+		}
+
+		record = rr.NewAForObject(owner, address)
+	case rr.RRTypeCNAME:
+		record = rr.NewCNAMEForObject(owner, dnsname.Canonical(value), address)
+	case rr.RRTypePTR:
+		record = rr.NewPTRForObject(owner, dnsname.Canonical(value), address)
+	case rr.RRTypeTXT:
+		record = rr.NewTXTForObject(owner, address, value)
+	case rr.RRTypeMX:
+		var numbers []int
+
+		numbers, value, err = parseRRSetValue(value, 1)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: MX must be priority and host: %w", ErrRRSetValue, owner, err)
+		}
+
+		record = rr.NewMXForObject(owner, address, rr.MX{Priority: numbers[0], Host: dnsname.Canonical(value)})
+	case rr.RRTypeSRV:
+		var numbers []int
+
+		numbers, value, err = parseRRSetValue(value, 3) //nolint:gomnd
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: SRV must be priority, weight, port and target: %w",
+				ErrRRSetValue, owner, err)
+		}
+
+		record = rr.NewSRVForObject(owner, address, rr.SRV{
+			Priority: numbers[0],
+			Weight:   numbers[1],
+			Port:     numbers[2],
+			Target:   dnsname.Canonical(value),
+		})
+	default:
+		return nil, fmt.Errorf("%w: %s: unsupported type %s", ErrRRSetValue, owner, recordType)
+	}
+
+	record.TTL = values["ttl"].(int)
+	record.Publishing = values["publishing"].(string)
+
+	return record, nil
+}
+
+// parseRRSetValue splits a value into the leading numbers and a single hostname.
+func parseRRSetValue(value string, count int) ([]int, string, error) {
+	fields := strings.Fields(value)
+	if len(fields) != count+1 {
+		return nil, "", fmt.Errorf("expected %d fields, got %d", count+1, len(fields))
+	}
+
+	numbers := make([]int, count)
+
+	for i := range numbers {
+		number, err := strconv.ParseUint(fields[i], 10, 16)
+		if err != nil {
+			return nil, "", fmt.Errorf("%q is not a number between 0 and 65535", fields[i])
+		}
+
+		numbers[i] = int(number)
+	}
+
+	return numbers, fields[count], nil
+}
+
+// flattenV4AddressRRSetRecord returns the record block of a record.
+//
+// Data that can not be decoded is returned as its raw fields, so the record is planned for replacement.
+func flattenV4AddressRRSetRecord(record *rr.RR) map[string]any {
+	values := map[string]any{
+		"owner":      dnsname.Canonical(record.Owner),
+		"type":       record.RRType,
+		"ttl":        record.TTL,
+		"publishing": record.Publishing,
+	}
+
+	value, err := formatV4AddressRRSetValue(record)
+	if err != nil {
+		value = strings.TrimSpace(strings.Join([]string{record.Data1, record.Data2, record.Data3, record.Data4}, " "))
+	}
+
+	values["value"] = value
+
+	return values
+}
+
+// formatV4AddressRRSetValue returns the value of a record block, see expandV4AddressRRSetRecord.
+func formatV4AddressRRSetValue(record *rr.RR) (string, error) {
+	switch record.RRType {
+	case rr.RRTypeA:
+		return record.Data1, nil
+	case rr.RRTypeCNAME, rr.RRTypePTR:
+		return dnsname.Canonical(record.Data1), nil
+	case rr.RRTypeTXT:
+		return record.TXT() //nolint:wrapcheck
+	case rr.RRTypeMX:
+		mx, err := record.MX()
+		if err != nil {
+			return "", err //nolint:wrapcheck
+		}
+
+		return fmt.Sprintf("%d %s", mx.Priority, dnsname.Canonical(mx.Host)), nil
+	case rr.RRTypeSRV:
+		srv, err := record.SRV()
+		if err != nil {
+			return "", err //nolint:wrapcheck
+		}
+
+		return fmt.Sprintf("%d %d %d %s", srv.Priority, srv.Weight, srv.Port, dnsname.Canonical(srv.Target)), nil
+	}
+
+	return "", fmt.Errorf("%w: unsupported type %s", ErrRRSetValue, record.RRType)
+}
+
+// normalizeV4AddressRRSetValue returns the value in the form read from QIP, invalid values are kept.
+func normalizeV4AddressRRSetValue(recordType, value string) string {
+	switch recordType {
+	case rr.RRTypeCNAME, rr.RRTypePTR:
+		return dnsname.Canonical(value)
+	case rr.RRTypeMX, rr.RRTypeSRV:
+		fields := strings.Fields(value)
+		if len(fields) > 0 {
+			fields[len(fields)-1] = dnsname.Canonical(fields[len(fields)-1])
+		}
+
+		return strings.Join(fields, " ")
+	}
+
+	return value
+}
+
+// hashV4AddressRRSetRecord hashes a record block in its normalized form, so only real changes replace a record.
+//
+//nolint:forcetypeassert
+func hashV4AddressRRSetRecord(value any) int {
+	values := value.(map[string]any)
+	recordType := values["type"].(string)
+
+	return schema.HashString(strings.Join([]string{
+		dnsname.Canonical(values["owner"].(string)),
+		recordType,
+		normalizeV4AddressRRSetValue(recordType, values["value"].(string)),
+		strconv.Itoa(values["ttl"].(int)),
+		values["publishing"].(string),
+	}, "/"))
+}
+
+// suppressV4AddressRRSetValueDiff ignores differences of the value not changing the record in QIP.
+func suppressV4AddressRRSetValueDiff(key, oldValue, newValue string, d *schema.ResourceData) bool {
+	recordType, _ := d.Get(strings.TrimSuffix(key, "value") + "type").(string)
+
+	return normalizeV4AddressRRSetValue(recordType, oldValue) == normalizeV4AddressRRSetValue(recordType, newValue)
+}
+
+// expandV4AddressRRSet returns the records of all record blocks.
+func expandV4AddressRRSet(address string, set *schema.Set) ([]*rr.RR, error) {
+	records := make([]*rr.RR, 0, set.Len())
+
+	for _, item := range set.List() {
+		record, err := expandV4AddressRRSetRecord(address, item.(map[string]any)) //nolint:forcetypeassert
+		if err != nil {
+			return nil, err
+		}
+
+		for _, other := range records {
+			if record.Equal(other) && record.DataEqual(other) {
+				return nil, fmt.Errorf("%w: %s %s", ErrRRSetDuplicate, record.RRType, record.Owner)
+			}
+		}
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// resourceV4AddressRRSetCheckRecords validates the values of the record blocks during plan.
+//
+//nolint:forcetypeassert
+func resourceV4AddressRRSetCheckRecords(_ context.Context, d *schema.ResourceDiff, _ any) error {
+	if !d.NewValueKnown("address") || !d.NewValueKnown("record") {
+		return nil
+	}
+
+	_, err := expandV4AddressRRSet(d.Get("address").(string), d.Get("record").(*schema.Set))
+	if err != nil {
+		return fmt.Errorf("record: %w", err)
+	}
+
+	return nil
+}
+
+// loadV4AddressRRSet returns all additional records of the object, nil is returned when the object is not found.
+func loadV4AddressRRSet(client *qip.Client, address string) ([]*rr.RR, error) {
+	records, err := rr.LoadAllForObject(client, address)
+	if err != nil {
+		var notFoundErr *qip.HTTPNotFoundError
+		if errors.As(err, &notFoundErr) {
+			return nil, nil
+		}
+
+		return nil, err //nolint:wrapcheck
+	}
+
+	if records == nil {
+		records = []*rr.RR{}
+	}
+
+	return records, nil
+}
+
+// applyV4AddressRRSet changes the records of the object in QIP to the configured records.
+//
+//nolint:forcetypeassert
+func applyV4AddressRRSet(ctx context.Context, d *schema.ResourceData, meta any, desired []*rr.RR) error {
+	client := meta.(*terraformClient).QIPClient
+	address := d.Get("address").(string)
+
+	current, err := loadV4AddressRRSet(client, address)
+	if err != nil {
+		return err
+	} else if current == nil {
+		return fmt.Errorf("%w: %s", ErrRRSetObjectNotFound, address)
+	}
+
+	changes := rr.Diff(current, desired)
+
+	tflog.Debug(ctx, "Applying RR set of V4Address "+address, map[string]any{
+		"create": len(changes.Create),
+		"update": len(changes.Update),
+		"delete": len(changes.Delete),
+	})
+
+	return rr.ApplyChanges(client, changes) //nolint:wrapcheck
+}
+
+//nolint:forcetypeassert
+func resourceV4AddressRRSetCreate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	address := d.Get("address").(string)
+
+	desired, err := expandV4AddressRRSet(address, d.Get("record").(*schema.Set))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = applyV4AddressRRSet(ctx, d, meta, desired)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(address)
+
+	return resourceV4AddressRRSetRead(ctx, d, meta)
+}
+
+func resourceV4AddressRRSetRead(_ context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	records, err := loadV4AddressRRSet(meta.(*terraformClient).QIPClient, d.Id()) //nolint:forcetypeassert
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if records == nil {
+		// The object was deleted together with its records
+		id := d.Id()
+		d.SetId("")
+
+		return diag.Diagnostics{removedRecordWarning(id)}
+	}
+
+	values := make([]any, 0, len(records))
+
+	for _, record := range records {
+		values = append(values, flattenV4AddressRRSetRecord(record))
+	}
+
+	err = d.Set("address", d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = d.Set("record", values)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+//nolint:forcetypeassert
+func resourceV4AddressRRSetUpdate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	desired, err := expandV4AddressRRSet(d.Get("address").(string), d.Get("record").(*schema.Set))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = applyV4AddressRRSet(ctx, d, meta, desired)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceV4AddressRRSetRead(ctx, d, meta)
+}
+
+func resourceV4AddressRRSetDelete(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	err := applyV4AddressRRSet(ctx, d, meta, nil)
+	if errors.Is(err, ErrRRSetObjectNotFound) {
+		// Nothing to delete
+		return nil
+	}
+
+	return diag.FromErr(err)
+}
+
+// resourceV4AddressRRSetImport imports the records of an object by its address.
+func resourceV4AddressRRSetImport(_ context.Context, d *schema.ResourceData, _ any) ([]*schema.ResourceData, error) {
+	if !isIPv4Address(d.Id()) {
+		return nil, fmt.Errorf("%w: %s", ErrRRSetImportID, d.Id())
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/rr"
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/test"
+)
+
+func TestExpandV4AddressRRSetRecord(t *testing.T) {
+	for _, value := range []map[string]any{
+		{"owner": "www.example.com", "type": rr.RRTypeA, "value": "192.0.2.50"},
+		{"owner": "alias.example.com", "type": rr.RRTypeCNAME, "value": "www.example.com"},
+		{"owner": "example.com", "type": rr.RRTypeMX, "value": "10 mail.example.com"},
+		{"owner": "_ldap._tcp.example.com", "type": rr.RRTypeSRV, "value": "0 5 389 dc.example.com"},
+		{"owner": "example.com", "type": rr.RRTypeTXT, "value": "v=spf1 mx -all"},
+	} {
+		value["ttl"] = 300
+		value["publishing"] = rr.PublishingAlways
+
+		record, err := expandV4AddressRRSetRecord("192.0.2.50", value)
+		require.NoError(t, err)
+		assert.Equal(t, "192.0.2.50", record.InfraAddr)
+		assert.Equal(t, 300, record.TTL)
+		assert.Equal(t, value, flattenV4AddressRRSetRecord(record))
+	}
+
+	for _, value := range []map[string]any{
+		{"owner": "www.example.com", "type": rr.RRTypeA, "value": "192.0.2.51"},
+		{"owner": "example.com", "type": rr.RRTypeMX, "value": "mail.example.com"},
+		{"owner": "_ldap._tcp.example.com", "type": rr.RRTypeSRV, "value": "0 5 70000 dc.example.com"},
+	} {
+		_, err := expandV4AddressRRSetRecord("192.0.2.50", value)
+		require.ErrorIs(t, err, ErrRRSetValue)
+	}
+}
+
+func TestHashV4AddressRRSetRecord(t *testing.T) {
+	record := map[string]any{
+		"owner": "example.com", "type": rr.RRTypeMX, "value": "10 mail.example.com",
+		"ttl": rr.TTLDefault, "publishing": rr.PublishingAlways,
+	}
+	configured := map[string]any{
+		"owner": "Example.com.", "type": rr.RRTypeMX, "value": "10  Mail.example.com.",
+		"ttl": rr.TTLDefault, "publishing": rr.PublishingAlways,
+	}
+
+	assert.Equal(t, hashV4AddressRRSetRecord(record), hashV4AddressRRSetRecord(configured))
+
+	configured["ttl"] = 300
+	assert.NotEqual(t, hashV4AddressRRSetRecord(record), hashV4AddressRRSetRecord(configured))
+}
+
+func TestResourceV4AddressRRSetApply(t *testing.T) {
+	c, cleanup := test.GetTestClient(t)
+	defer cleanup()
+
+	url := test.QIPServer + "/api/v1/" + test.QIPOrg + "/rr"
+
+	httpmock.RegisterResponder("GET", url+".json", httpmock.NewStringResponder(200, `{"list":[
+		{"owner":"www.example.com","classType":"IN","rrType":"A","data1":"192.0.2.50","ttl":-1,
+			"publishing":"ALWAYS","infraType":"OBJECT","infraAddr":"192.0.2.50"},
+		{"owner":"manual.example.com","classType":"IN","rrType":"A","data1":"192.0.2.50","ttl":-1,
+			"publishing":"ALWAYS","infraType":"OBJECT","infraAddr":"192.0.2.50"}]}`))
+	httpmock.RegisterResponder("DELETE", url, httpmock.NewStringResponder(200, `OK`))
+	httpmock.RegisterResponder("POST", url, httpmock.NewStringResponder(200, `OK`))
+
+	meta := &terraformClient{QIPClient: c}
+	d := schema.TestResourceDataRaw(t, resourceV4AddressRRSet().Schema, map[string]any{"address": "192.0.2.50"})
+	d.SetId("192.0.2.50")
+
+	// Records added outside of Terraform are part of the state
+	diags := resourceV4AddressRRSetRead(context.Background(), d, meta)
+	require.False(t, diags.HasError())
+	assert.Equal(t, 2, d.Get("record.#"))
+
+	desired := []*rr.RR{
+		rr.NewAForObject("www.example.com", "192.0.2.50"),
+		rr.NewCNAMEForObject("alias.example.com", "www.example.com", "192.0.2.50"),
+	}
+
+	err := applyV4AddressRRSet(context.Background(), d, meta, desired)
+	require.NoError(t, err)
+
+	info := httpmock.GetCallCountInfo()
+	assert.Equal(t, 1, info["DELETE "+url])
+	assert.Equal(t, 1, info["POST "+url])
+}
+
+func TestResourceV4AddressRRSetRead_ObjectNotFound(t *testing.T) {
+	c, cleanup := test.GetTestClient(t)
+	defer cleanup()
+
+	httpmock.RegisterResponder("GET", test.QIPServer+"/api/v1/"+test.QIPOrg+"/rr.json",
+		httpmock.NewStringResponder(404, `not found`))
+
+	meta := &terraformClient{QIPClient: c}
+	d := schema.TestResourceDataRaw(t, resourceV4AddressRRSet().Schema, map[string]any{"address": "192.0.2.50"})
+	d.SetId("192.0.2.50")
+
+	diags := resourceV4AddressRRSetRead(context.Background(), d, meta)
+	require.False(t, diags.HasError())
+	assert.Empty(t, d.Id())
+
+	diags = resourceV4AddressRRSetDelete(context.Background(), d, meta)
+	require.False(t, diags.HasError())
+}
+
+func TestAccResourceV4AddressRRSet(t *testing.T) {
+	subnet := getRequiredEnv(t, "QIP_TEST_ACC_RESOURCE_SUBNET")
+	name := getRandomName("terraform-qip-set")
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+					resource "qip_v4address" "test" {
+						subnet  = "` + subnet + `"
+						name    = "` + name + `"
+					}
+
+					resource "qip_v4address_rr_set" "test" {
+						address = qip_v4address.test.address
+
+						record {
+							owner = "` + name + `-extra.${qip_v4address.test.domain_name}"
+							type  = "A"
+							value = qip_v4address.test.address
+						}
+
+						record {
+							owner = "` + name + `-alias.${qip_v4address.test.domain_name}"
+							type  = "CNAME"
+							value = "` + name + `.${qip_v4address.test.domain_name}"
+						}
+					}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("qip_v4address_rr_set.test", "record.#", "2"),
+				),
+			},
+			{
+				ResourceName:      "qip_v4address_rr_set.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
@@ -19,11 +19,256 @@ limitations under the License.
 package provider
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/rr"
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/test"
 )
 
+// getIDFromRR returns the ID of version 0 of the record resources, a base64 encoded JSON representation of a record.
+func getIDFromRR(record *rr.RR) (string, error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return "", fmt.Errorf("could not encode JSON: %w", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+func TestParseV4AddressRRID(t *testing.T) {
+	record, err := parseV4AddressRRID("192.0.2.50/a/WWW.example.com.")
+	require.NoError(t, err)
+	assert.True(t, record.Equal(rr.NewAForObject("www.example.com", "192.0.2.50")))
+	assert.Equal(t, "192.0.2.50/A/www.example.com", formatV4AddressRRID(record))
+
+	for _, id := range []string{"", "192.0.2.50", "192.0.2.50/A", "192.0.2.50//www.example.com", "host/A/www.example.com"} {
+		_, err = parseV4AddressRRID(id)
+		require.ErrorIs(t, err, ErrV4AddressRRID, id)
+	}
+}
+
+func TestResourceV4AddressRRStateUpgradeV0(t *testing.T) {
+	oldID, err := getIDFromRR(rr.NewAForObject("www.example.com", "192.0.2.50"))
+	require.NoError(t, err)
+
+	state, err := resourceV4AddressRRStateUpgradeV0(context.Background(), map[string]any{
+		"id":          oldID,
+		"address":     "192.0.2.50",
+		"name":        "www",
+		"domain_name": "example.com",
+	}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "192.0.2.50/A/www.example.com", state["id"])
+	assert.Equal(t, "www", state["name"])
+
+	// Upgrading again keeps the ID
+	state, err = resourceV4AddressRRStateUpgradeV0(context.Background(), state, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "192.0.2.50/A/www.example.com", state["id"])
+
+	_, err = resourceV4AddressRRStateUpgradeV0(context.Background(), map[string]any{"id": "not base64"}, nil)
+	require.Error(t, err)
+}
+
+func TestResourceV4AddressRRRead(t *testing.T) {
+	c, cleanup := test.GetTestClient(t)
+	defer cleanup()
+
+	httpmock.RegisterResponder("GET", test.QIPServer+"/api/v1/"+test.QIPOrg+"/rr.json",
+		httpmock.NewStringResponder(200, `{"list":[{"owner":"www.corp.example.com","classType":"IN","rrType":"A",
+			"data1":"192.0.2.50","infraType":"OBJECT","infraAddr":"192.0.2.50","ttl":300,"publishing":"INTERNAL"}]}`))
+
+	d := schema.TestResourceDataRaw(t, resourceV4AddressRR().Schema, map[string]any{})
+	d.SetId("192.0.2.50/A/www.corp.example.com")
+
+	diags := resourceV4AddressRRRead(context.Background(), d, &terraformClient{QIPClient: c})
+	require.False(t, diags.HasError())
+	assert.Equal(t, 300, d.Get("ttl"))
+	assert.Equal(t, rr.PublishingInternal, d.Get("publishing"))
+
+	// Deleted outside of Terraform
+	d.SetId("192.0.2.50/A/missing.corp.example.com")
+
+	diags = resourceV4AddressRRRead(context.Background(), d, &terraformClient{QIPClient: c})
+	require.False(t, diags.HasError())
+	assert.Len(t, diags, 1)
+	assert.Empty(t, d.Id())
+}
+
+func TestResourceV4AddressRR_RemoveExtras(t *testing.T) {
+	c, cleanup := test.GetTestClient(t)
+	defer cleanup()
+
+	url := test.QIPServer + "/api/v1/" + test.QIPOrg + "/rr"
+	record := `{"owner":"www.corp.example.com","classType":"IN","rrType":"A","data1":"192.0.2.50",
+		"infraType":"OBJECT","infraAddr":"192.0.2.50","ttl":-1,"publishing":"ALWAYS"}`
+
+	httpmock.RegisterResponder("GET", url+".json",
+		httpmock.NewStringResponder(200, `{"list":[`+record+`,`+record+`]}`))
+	httpmock.RegisterResponder("DELETE", url, httpmock.NewStringResponder(200, `OK`))
+
+	var (
+		ctx    = context.Background()
+		meta   = &terraformClient{QIPClient: c}
+		res    = resourceV4AddressRR()
+		config = map[string]any{
+			"address":          "192.0.2.50",
+			"name":             "www",
+			"domain_name":      "corp.example.com",
+			"duplicate_policy": DuplicatePolicyRemoveExtras,
+		}
+	)
+
+	d := schema.TestResourceDataRaw(t, res.Schema, config)
+	d.SetId("192.0.2.50/A/www.corp.example.com")
+
+	// Read only reports the duplicates
+	diags := resourceV4AddressRRRead(ctx, d, meta)
+	require.Len(t, diags, 1)
+	assert.Equal(t, diag.Warning, diags[0].Severity)
+	assert.Equal(t, 1, d.Get("duplicates"))
+	assert.Zero(t, httpmock.GetCallCountInfo()["DELETE "+url])
+
+	diff, err := res.Diff(ctx, d.State(), terraform.NewResourceConfigRaw(config), meta)
+	require.NoError(t, err)
+	require.NotNil(t, diff)
+	require.Contains(t, diff.Attributes, "duplicates")
+	assert.Equal(t, "0", diff.Attributes["duplicates"].New)
+
+	// The apply removes them
+	diags = resourceV4AddressRRUpdate(ctx, d, meta)
+	require.False(t, diags.HasError())
+	assert.Equal(t, 1, httpmock.GetCallCountInfo()["DELETE "+url])
+	assert.Equal(t, 0, d.Get("duplicates"))
+}
+
+func TestResourceV4AddressRRDelete_CreatePTR(t *testing.T) {
+	c, cleanup := test.GetTestClient(t)
+	defer cleanup()
+
+	url := test.QIPServer + "/api/v1/" + test.QIPOrg + "/rr"
+
+	httpmock.RegisterResponderWithQuery("GET", url+".json", "type=OBJECT&address=192.0.2.50&getDefaultRRs=false",
+		httpmock.NewStringResponder(200, `{"list":[{"owner":"www.corp.example.com","classType":"IN","rrType":"A",
+			"data1":"192.0.2.50","infraType":"OBJECT","infraAddr":"192.0.2.50"}]}`))
+	httpmock.RegisterResponderWithQuery("GET", url+".json", "owner=50.2.0.192.in-addr.arpa&getDefaultRRs=false",
+		httpmock.NewStringResponder(200, `{"list":[{"owner":"50.2.0.192.in-addr.arpa","classType":"IN","rrType":"PTR",
+			"data1":"www.corp.example.com","infraType":"V4REVERSEZONE","infraFQDN":"2.0.192.in-addr.arpa"}]}`))
+	httpmock.RegisterResponder("DELETE", url, httpmock.NewStringResponder(200, `OK`))
+
+	d := schema.TestResourceDataRaw(t, resourceV4AddressRR().Schema, map[string]any{"create_ptr": true})
+	d.SetId("192.0.2.50/A/www.corp.example.com")
+	require.NoError(t, d.Set("ptr_created", true))
+
+	diags := resourceV4AddressRRDelete(context.Background(), d, &terraformClient{QIPClient: c})
+	require.False(t, diags.HasError())
+
+	// The A record and its PTR
+	assert.Equal(t, 2, httpmock.GetCallCountInfo()["DELETE "+url])
+
+	// The PTR existed before, e.g. managed by qip_zone_record
+	httpmock.ZeroCallCounters()
+	require.NoError(t, d.Set("ptr_created", false))
+
+	diags = resourceV4AddressRRDelete(context.Background(), d, &terraformClient{QIPClient: c})
+	require.False(t, diags.HasError())
+	assert.Equal(t, 1, httpmock.GetCallCountInfo()["DELETE "+url])
+}
+
+func TestResourceV4AddressRRCreate_ExistingPTR(t *testing.T) {
+	c, cleanup := test.GetTestClient(t)
+	defer cleanup()
+
+	url := test.QIPServer + "/api/v1/" + test.QIPOrg + "/rr"
+	ptr := `{"owner":"50.2.0.192.in-addr.arpa","classType":"IN","rrType":"PTR","data1":"www.corp.example.com",
+		"infraType":"V4REVERSEZONE","infraFQDN":"2.0.192.in-addr.arpa"}`
+	ptrStatus, ptrs := 200, `{"list":[`+ptr+`]}`
+
+	httpmock.RegisterResponderWithQuery("GET", url+".json", "owner=www.corp.example.com&getDefaultRRs=true",
+		httpmock.NewStringResponder(404, ""))
+	httpmock.RegisterResponderWithQuery("GET", url+".json", "owner=50.2.0.192.in-addr.arpa&getDefaultRRs=false",
+		func(*http.Request) (*http.Response, error) {
+			return httpmock.NewStringResponse(ptrStatus, ptrs), nil
+		})
+	httpmock.RegisterResponder("POST", url, httpmock.NewStringResponder(201, ""))
+
+	meta := &terraformClient{QIPClient: c}
+	config := map[string]any{
+		"address":     "192.0.2.50",
+		"name":        "www",
+		"domain_name": "corp.example.com",
+		"create_ptr":  true,
+	}
+
+	// The PTR already existed
+	d := schema.TestResourceDataRaw(t, resourceV4AddressRR().Schema, config)
+
+	diags := resourceV4AddressRRCreate(context.Background(), d, meta)
+	require.False(t, diags.HasError())
+	assert.False(t, d.Get("ptr_created").(bool))
+
+	// QIP created the PTR, before QIP had no records for the reverse name
+	ptrStatus, ptrs = 404, ""
+
+	httpmock.RegisterResponder("POST", url, func(*http.Request) (*http.Response, error) {
+		ptrStatus, ptrs = 200, `{"list":[`+ptr+`]}`
+
+		return httpmock.NewStringResponse(201, ""), nil
+	})
+
+	d = schema.TestResourceDataRaw(t, resourceV4AddressRR().Schema, config)
+
+	diags = resourceV4AddressRRCreate(context.Background(), d, meta)
+	require.False(t, diags.HasError(), diags)
+	assert.True(t, d.Get("ptr_created").(bool))
+	assert.Equal(t, 1, httpmock.GetCallCountInfo()["POST "+url])
+}
+
+func TestResourceV4AddressRRImport(t *testing.T) {
+	c, cleanup := test.GetTestClient(t)
+	defer cleanup()
+
+	baseURL := test.QIPServer + "/api/v1/" + test.QIPOrg
+
+	httpmock.RegisterResponder("GET", baseURL+"/rr.json",
+		httpmock.NewStringResponder(200, `{"list":[{"owner":"www.corp.example.com","classType":"IN","rrType":"A",
+			"data1":"192.0.2.50","infraType":"OBJECT","infraAddr":"192.0.2.50"}]}`))
+	httpmock.RegisterResponder("GET", baseURL+"/v4address/192.0.2.50.json",
+		httpmock.NewStringResponder(200, `{"objectAddr":"192.0.2.50","objectName":"host","domainName":"corp.example.com"}`))
+
+	meta := &terraformClient{QIPClient: c}
+	d := schema.TestResourceDataRaw(t, resourceV4AddressRR().Schema, map[string]any{})
+	d.SetId("192.0.2.50/A/WWW.corp.example.com")
+
+	result, err := resourceV4AddressRRImport(context.Background(), d, meta)
+	require.NoError(t, err)
+
+	if assert.Len(t, result, 1) {
+		assert.Equal(t, "192.0.2.50/A/www.corp.example.com", result[0].Id())
+		assert.Equal(t, "www", result[0].Get("name"))
+		assert.Equal(t, "corp.example.com", result[0].Get("domain_name"))
+		assert.False(t, result[0].Get("create_ptr").(bool))
+	}
+
+	d.SetId("192.0.2.50/A/missing.corp.example.com")
+
+	_, err = resourceV4AddressRRImport(context.Background(), d, meta)
+	require.ErrorIs(t, err, ErrRRNotFound)
+}
+
 func TestAccResourceV4AddressRR(t *testing.T) {
 	subnet := getRequiredEnv(t, "QIP_TEST_ACC_RESOURCE_SUBNET")
 	name := getRandomName("terraform-qip-rr")
@@ -62,14 +307,23 @@ func TestAccResourceV4AddressRR(t *testing.T) {
 						name        = "*.` + name + `"
 						address     = qip_v4address.test.address
 						domain_name = qip_v4address.test.domain_name
+						ttl         = 300
+						publishing  = "INTERNAL"
 					}
 				`,
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestMatchResourceAttr("qip_v4address.test", "name", stringNoWhitespaceRe),
 					resource.TestMatchResourceAttr("qip_v4address_rr.test", "address", stringNoWhitespaceRe),
 					resource.TestMatchResourceAttr("qip_v4address_rr.test", "name", stringRe(`*.`+name)),
+					resource.TestCheckResourceAttr("qip_v4address_rr.test", "ttl", "300"),
+					resource.TestCheckResourceAttr("qip_v4address_rr.test", "publishing", "INTERNAL"),
 				),
 			},
+			{
+				ResourceName:      "qip_v4address_rr.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
 		},
 	})
 }
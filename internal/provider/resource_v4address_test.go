@@ -19,10 +19,17 @@ limitations under the License.
 package provider
 
 import (
+	"context"
 	"regexp"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/test"
 )
 
 func TestAccResourceV4Address(t *testing.T) {
@@ -67,6 +74,12 @@ func TestAccResourceV4Address(t *testing.T) {
 					resource.TestMatchResourceAttr("qip_v4address.test", "description", regexp.MustCompile(`^Added`)),
 				),
 			},
+			{
+				ResourceName:      "qip_v4address.test",
+				ImportState:       true,
+				ImportStateId:     subnet + "/" + address,
+				ImportStateVerify: true,
+			},
 		},
 	})
 }
@@ -133,3 +146,40 @@ func TestAccResourceV4Address_WithSelectRange(t *testing.T) {
 		},
 	})
 }
+
+func TestCheckMoveAddress(t *testing.T) {
+	err := checkMoveAddress("192.0.2.0", "198.51.100.0", "192.0.2.50", "192.0.2.50")
+	require.ErrorIs(t, err, ErrMoveSameAddress)
+	assert.Contains(t, err.Error(), "192.0.2.50 is in 192.0.2.0")
+
+	require.NoError(t, checkMoveAddress("192.0.2.0", "198.51.100.0", "192.0.2.50", "198.51.100.7"))
+}
+
+func TestReleaseSelectedAddress(t *testing.T) {
+	c, cleanup := test.GetTestClient(t)
+	defer cleanup()
+
+	backoff := selectReleaseBackoff
+	selectReleaseBackoff = 0
+
+	t.Cleanup(func() { selectReleaseBackoff = backoff })
+
+	url := test.QIPServer + "/api/v1/" + test.QIPOrg + "/selectedv4address/192.0.2.25/"
+
+	// Fails once, then succeeds
+	httpmock.RegisterResponder("DELETE", url,
+		httpmock.NewStringResponder(500, ``).Then(httpmock.NewStringResponder(200, ``)))
+
+	diags := releaseSelectedAddress(context.Background(), c, "192.0.2.25")
+	require.Empty(t, diags)
+	assert.Equal(t, 2, httpmock.GetTotalCallCount())
+
+	// Always fails
+	httpmock.RegisterResponder("DELETE", url, httpmock.NewStringResponder(500, ``))
+
+	diags = releaseSelectedAddress(context.Background(), c, "192.0.2.25")
+	if assert.Len(t, diags, 1) {
+		assert.Equal(t, diag.Warning, diags[0].Severity)
+		assert.Contains(t, diags[0].Summary, "192.0.2.25")
+	}
+}
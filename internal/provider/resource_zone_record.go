@@ -0,0 +1,380 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/dnsname"
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip"
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/rr"
+)
+
+// zoneRecordIDParts is the number of parts of a zone record ID (zone/TYPE/owner).
+const zoneRecordIDParts = 3
+
+var (
+	ErrZoneRecordID              = errors.New("ID must be zone/TYPE/owner")
+	ErrZoneRecordNotFound        = errors.New("no record found in zone")
+	ErrZoneRecordAmbiguousImport = errors.New("more than one record found in zone")
+)
+
+// zoneRecordTypes are the record types of qip_zone_record.
+var zoneRecordTypes = []string{rr.RRTypeAAAA, rr.RRTypeMX, rr.RRTypeSRV, rr.RRTypeTXT, rr.RRTypePTR}
+
+func resourceZoneRecord() *schema.Resource {
+	return &schema.Resource{
+		Description: "Managing an AAAA, MX, SRV, TXT or PTR record of a DNS zone in QIP, which is not attached to an " +
+			"address object. (e.g. TXT records for domain verification) The data is configured in the block " +
+			"matching `type`. Reverse zones (`in-addr.arpa` and `ip6.arpa`) are supported for PTR records.",
+
+		CreateContext: resourceZoneRecordCreate,
+		ReadContext:   resourceZoneRecordRead,
+		UpdateContext: resourceZoneRecordUpdate,
+		DeleteContext: resourceZoneRecordDelete,
+
+		CustomizeDiff: checkDNSRecordType(zoneRecordTypes),
+
+		Schema: mergeSchemas(schemaDNSRecordData(zoneRecordTypes), map[string]*schema.Schema{
+			"zone": {
+				Description:      "DNS Zone the record belongs to, forward or reverse zone.",
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validateDomainName,
+				DiffSuppressFunc: suppressNormalizedDiff(normalizeHostname),
+			},
+			"type": schemaDNSRecordType(zoneRecordTypes),
+			"name": {
+				Description: "Owner name of the record, relative to `zone`. (e.g. `_acme-challenge`) " +
+					"Leave empty for a record at the zone apex.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: validateHostname(true),
+				DiffSuppressFunc: suppressNormalizedDiff(normalizeHostname),
+			},
+			"ttl": schemaRecordTTL(),
+			"fqdn": {
+				Description: "FQDN of the record owner.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		}),
+
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceZoneRecordImport,
+		},
+
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourceZoneRecordResourceV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceZoneRecordStateUpgradeV0,
+			},
+		},
+	}
+}
+
+// resourceZoneRecordResourceV0 is the schema of version 0, which used a base64 encoded record as ID.
+func resourceZoneRecordResourceV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: mergeSchemas(schemaDNSRecordData(zoneRecordTypes), map[string]*schema.Schema{
+			"zone": {Type: schema.TypeString, Required: true},
+			"type": {Type: schema.TypeString, Required: true},
+			"name": {Type: schema.TypeString, Optional: true},
+			"ttl":  {Type: schema.TypeInt, Optional: true},
+			"fqdn": {Type: schema.TypeString, Computed: true},
+		}),
+	}
+}
+
+// resourceZoneRecordStateUpgradeV0 converts the base64 encoded ID of version 0 to the readable format,
+// see formatZoneRecordID.
+func resourceZoneRecordStateUpgradeV0(_ context.Context, rawState map[string]any, _ any) (map[string]any, error) {
+	return upgradeRRIDV0(rawState, "qip_zone_record", formatZoneRecordID, parseZoneRecordID)
+}
+
+// formatZoneRecordID returns the readable ID of a record of a zone, e.g. example.com/SRV/_ldap._tcp.
+//
+// The owner is relative to the zone, `@` is used for the zone apex.
+func formatZoneRecordID(record *rr.RR) string {
+	zone := dnsname.Canonical(record.InfraFQDN)
+
+	owner := "@"
+	if !dnsname.Equal(record.Owner, zone) {
+		owner = dnsname.Relative(record.Owner, zone)
+	}
+
+	return zone + "/" + record.RRType + "/" + owner
+}
+
+// parseZoneRecordID returns the identifying attributes of a record from an ID, see formatZoneRecordID.
+func parseZoneRecordID(id string) (*rr.RR, error) {
+	parts := strings.SplitN(id, "/", zoneRecordIDParts)
+	if len(parts) != zoneRecordIDParts || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("%w: %s", ErrZoneRecordID, id)
+	}
+
+	zone := dnsname.Canonical(parts[0])
+
+	owner := parts[2]
+	if owner == "@" {
+		owner = ""
+	}
+
+	return rr.New(rr.ForZoneName(zone), dnsname.Join(owner, zone), strings.ToUpper(parts[1])), nil
+}
+
+// expandZoneRecord returns the record for the configuration.
+//
+//nolint:forcetypeassert
+func expandZoneRecord(d dnsRecordData) (*rr.RR, error) {
+	zone := d.Get("zone").(string)
+
+	record, err := expandDNSRecordData(d, rr.ForZoneName(zone), dnsname.Join(d.Get("name").(string), zone))
+	if err != nil {
+		return nil, err
+	}
+
+	record.TTL = d.Get("ttl").(int)
+
+	return record, nil
+}
+
+// loadZoneRecord returns the record of the ID, nil is returned when it is not found.
+//
+// Other records of the owner only differ by their data, which is taken from data: the state, or the prior
+// state during an update. See selectRecord.
+func loadZoneRecord(d *schema.ResourceData, data dnsRecordData, meta any) (*rr.RR, error) {
+	if d.Id() == "" {
+		return nil, ErrIDRequiredToLoad
+	}
+
+	idRecord, err := parseZoneRecordID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	idRecord = recordWithData(idRecord, data)
+
+	records, err := loadZoneRecords(meta.(*terraformClient).QIPClient, idRecord) //nolint:forcetypeassert
+	if err != nil {
+		return nil, err
+	}
+
+	return selectRecord(records, idRecord)
+}
+
+// loadZoneRecords returns the records of the zone with the owner and type of idRecord.
+//
+// Only the records of the owner are requested from QIP, not the whole zone.
+func loadZoneRecords(client *qip.Client, idRecord *rr.RR) ([]*rr.RR, error) {
+	records, err := rr.Search(client, &rr.Query{Owner: dnsname.Canonical(idRecord.Owner), RRType: idRecord.RRType})
+	if err != nil {
+		var notFoundErr *qip.HTTPNotFoundError
+		if errors.As(err, &notFoundErr) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("could not load records of zone %s: %w", idRecord.InfraFQDN, err)
+	}
+
+	var matching []*rr.RR
+
+	for _, record := range records {
+		// Records of objects can have the same owner
+		if record.Equal(idRecord) {
+			matching = append(matching, record)
+		}
+	}
+
+	return matching, nil
+}
+
+func resourceZoneRecordCreate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*terraformClient).QIPClient //nolint:forcetypeassert
+
+	record, err := expandZoneRecord(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = rr.CheckConflict(client, record, nil)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = rr.Create(client, record)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(formatZoneRecordID(record))
+
+	tflog.Trace(ctx, "Created "+record.RRType+" record "+record.Owner+" in zone "+record.InfraFQDN)
+
+	return resourceZoneRecordRead(ctx, d, meta)
+}
+
+func resourceZoneRecordRead(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	record, err := loadZoneRecord(d, d, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if record == nil {
+		diags := diag.Diagnostics{removedRecordWarning(d.Id())}
+
+		d.SetId("")
+
+		return diags
+	}
+
+	values, err := flattenDNSRecordBlocks(record, zoneRecordTypes)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	zone := dnsname.Canonical(record.InfraFQDN)
+
+	values["zone"] = zone
+	values["type"] = record.RRType
+	values["name"] = dnsname.Relative(record.Owner, zone)
+	values["ttl"] = record.TTL
+	values["fqdn"] = dnsname.Canonical(record.Owner)
+
+	if dnsname.Equal(record.Owner, zone) {
+		// Record at the zone apex
+		values["name"] = ""
+	}
+
+	for k, v := range values {
+		err = d.Set(k, v)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return nil
+}
+
+func resourceZoneRecordUpdate(ctx context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	client := meta.(*terraformClient).QIPClient //nolint:forcetypeassert
+
+	record, err := loadZoneRecord(d, priorDNSRecordData{d}, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if record == nil {
+		return diag.Errorf("could not find a record for id: %s", d.Id())
+	}
+
+	expanded, err := expandZoneRecord(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	updatedRecord := *record
+	updatedRecord.Owner = expanded.Owner
+	updatedRecord.TTL = expanded.TTL
+	updatedRecord.Data1 = expanded.Data1
+	updatedRecord.Data2 = expanded.Data2
+	updatedRecord.Data3 = expanded.Data3
+	updatedRecord.Data4 = expanded.Data4
+
+	if !dnsname.Equal(record.Owner, updatedRecord.Owner) {
+		err = rr.CheckConflict(client, &updatedRecord, record)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	err = rr.Update(client, record, &updatedRecord)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(formatZoneRecordID(&updatedRecord))
+
+	return resourceZoneRecordRead(ctx, d, meta)
+}
+
+func resourceZoneRecordDelete(_ context.Context, d *schema.ResourceData, meta any) diag.Diagnostics {
+	record, err := loadZoneRecord(d, d, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if record == nil {
+		// Nothing to delete
+		return nil
+	}
+
+	err = rr.Delete(meta.(*terraformClient).QIPClient, record) //nolint:forcetypeassert
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// resourceZoneRecordImport resolves an ID zone/TYPE/owner to the single matching record of the zone.
+//
+// The owner can be relative to the zone, `@` selects the zone apex.
+func resourceZoneRecordImport(_ context.Context, d *schema.ResourceData, meta any) ([]*schema.ResourceData, error) {
+	record, err := findZoneRecord(meta.(*terraformClient).QIPClient, d.Id()) //nolint:forcetypeassert
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(formatZoneRecordID(record))
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// findZoneRecord returns the single record for an import ID zone/TYPE/owner, see parseZoneRecordID.
+func findZoneRecord(client *qip.Client, importID string) (*rr.RR, error) {
+	idRecord, err := parseZoneRecordID(importID)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := loadZoneRecords(client, idRecord)
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(records) {
+	case 0:
+		return nil, fmt.Errorf("%w: %s %s", ErrZoneRecordNotFound, idRecord.RRType, idRecord.Owner)
+	case 1:
+		return records[0], nil
+	}
+
+	return nil, fmt.Errorf("%w: %s %s", ErrZoneRecordAmbiguousImport, idRecord.RRType, idRecord.Owner)
+}
@@ -0,0 +1,270 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/rr"
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/test"
+)
+
+const testZoneRecords = `{"list":[
+	{"owner":"example.com","classType":"IN","rrType":"TXT","data1":"\"verification=abc\"","ttl":300,
+		"infraType":"ZONE","infraFQDN":"example.com"},
+	{"owner":"_ldap._tcp.example.com","classType":"IN","rrType":"SRV","data1":"0","data2":"5","data3":"389",
+		"data4":"dc1.example.com","ttl":-1,"infraType":"ZONE","infraFQDN":"example.com"},
+	{"owner":"_ldap._tcp.example.com","classType":"IN","rrType":"SRV","data1":"0","data2":"5","data3":"389",
+		"data4":"dc2.example.com","ttl":-1,"infraType":"ZONE","infraFQDN":"example.com"}]}`
+
+// registerZoneRecordResponders returns the records of testZoneRecords by owner, the zone is never loaded.
+func registerZoneRecordResponders() {
+	url := test.QIPServer + "/api/v1/" + test.QIPOrg + "/rr.json"
+
+	httpmock.RegisterResponderWithQuery("GET", url, "owner=example.com&getDefaultRRs=false",
+		httpmock.NewStringResponder(200, `{"list":[
+			{"owner":"example.com","classType":"IN","rrType":"TXT","data1":"\"verification=abc\"","ttl":300,
+				"infraType":"ZONE","infraFQDN":"example.com"},
+			{"owner":"example.com","classType":"IN","rrType":"TXT","data1":"\"object\"",
+				"infraType":"OBJECT","infraAddr":"192.0.2.50"}]}`))
+	httpmock.RegisterResponderWithQuery("GET", url, "owner=_ldap._tcp.example.com&getDefaultRRs=false",
+		httpmock.NewStringResponder(200, `{"list":[
+			{"owner":"_ldap._tcp.example.com","classType":"IN","rrType":"SRV","data1":"0","data2":"5","data3":"389",
+				"data4":"dc1.example.com","ttl":-1,"infraType":"ZONE","infraFQDN":"example.com"},
+			{"owner":"_ldap._tcp.example.com","classType":"IN","rrType":"SRV","data1":"0","data2":"5","data3":"389",
+				"data4":"dc2.example.com","ttl":-1,"infraType":"ZONE","infraFQDN":"example.com"}]}`))
+	httpmock.RegisterResponderWithQuery("GET", url, "owner=missing.example.com&getDefaultRRs=false",
+		httpmock.NewStringResponder(404, ""))
+}
+
+func TestFindZoneRecord(t *testing.T) {
+	c, cleanup := test.GetTestClient(t)
+	defer cleanup()
+
+	registerZoneRecordResponders()
+
+	record, err := findZoneRecord(c, "Example.com/txt/@")
+	require.NoError(t, err)
+	assert.Equal(t, `"verification=abc"`, record.Data1)
+
+	_, err = findZoneRecord(c, "example.com/SRV/_ldap._tcp")
+	require.ErrorIs(t, err, ErrZoneRecordAmbiguousImport)
+
+	_, err = findZoneRecord(c, "example.com/MX/@")
+	require.ErrorIs(t, err, ErrZoneRecordNotFound)
+
+	_, err = findZoneRecord(c, "example.com/TXT/missing")
+	require.ErrorIs(t, err, ErrZoneRecordNotFound)
+
+	_, err = findZoneRecord(c, "example.com/TXT")
+	require.ErrorIs(t, err, ErrZoneRecordID)
+}
+
+func TestZoneRecordID(t *testing.T) {
+	record := rr.NewSRV(rr.ForZone("example.com"), "_ldap._tcp.example.com",
+		rr.SRV{Priority: 0, Weight: 5, Port: 389, Target: "dc1.example.com"})
+	assert.Equal(t, "example.com/SRV/_ldap._tcp", formatZoneRecordID(record))
+
+	record = rr.NewTXT(rr.ForZone("example.com"), "example.com", "verification=abc")
+	assert.Equal(t, "example.com/TXT/@", formatZoneRecordID(record))
+
+	parsed, err := parseZoneRecordID("Example.com/txt/@")
+	require.NoError(t, err)
+	assert.True(t, parsed.Equal(record))
+
+	parsed, err = parseZoneRecordID("2.0.192.in-addr.arpa/PTR/50")
+	require.NoError(t, err)
+	assert.Equal(t, rr.InfraTypeV4ReverseZone, parsed.InfraType)
+	assert.Equal(t, "50.2.0.192.in-addr.arpa", parsed.Owner)
+
+	_, err = parseZoneRecordID("example.com/TXT")
+	require.ErrorIs(t, err, ErrZoneRecordID)
+}
+
+func TestResourceZoneRecordStateUpgradeV0(t *testing.T) {
+	// The persisted shape of version 0 has the blocks of all zone record types
+	for _, recordType := range zoneRecordTypes {
+		assert.Contains(t, resourceZoneRecordResourceV0().Schema, dnsRecordBlocks[recordType])
+	}
+
+	id, err := getIDFromRR(rr.NewTXT(rr.ForZone("example.com"), "_acme-challenge.example.com", "abc"))
+	require.NoError(t, err)
+
+	state, err := resourceZoneRecordStateUpgradeV0(context.Background(), map[string]any{"id": id}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "example.com/TXT/_acme-challenge", state["id"])
+
+	// Already converted
+	state, err = resourceZoneRecordStateUpgradeV0(context.Background(), state, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "example.com/TXT/_acme-challenge", state["id"])
+
+	_, err = resourceZoneRecordStateUpgradeV0(context.Background(), map[string]any{"id": "not base64"}, nil)
+	require.Error(t, err)
+}
+
+func TestLoadZoneRecord(t *testing.T) {
+	c, cleanup := test.GetTestClient(t)
+	defer cleanup()
+
+	registerZoneRecordResponders()
+
+	meta := &terraformClient{QIPClient: c}
+
+	// Data changed outside of Terraform
+	d := schema.TestResourceDataRaw(t, resourceZoneRecord().Schema, map[string]any{
+		"zone": "example.com",
+		"type": rr.RRTypeTXT,
+		"txt":  []any{map[string]any{"value": "verification=old"}},
+	})
+	d.SetId("example.com/TXT/@")
+
+	record, err := loadZoneRecord(d, d, meta)
+	require.NoError(t, err)
+
+	if assert.NotNil(t, record) {
+		assert.Equal(t, 300, record.TTL)
+	}
+
+	diags := resourceZoneRecordRead(context.Background(), d, meta)
+	require.False(t, diags.HasError())
+	assert.Equal(t, "verification=abc", d.Get("txt.0.value"))
+	assert.Equal(t, "", d.Get("name"))
+	assert.Equal(t, 300, d.Get("ttl"))
+	assert.Equal(t, "example.com/TXT/@", d.Id())
+
+	// Only the exact record when the owner has more than one
+	d = schema.TestResourceDataRaw(t, resourceZoneRecord().Schema, map[string]any{
+		"zone": "example.com",
+		"type": rr.RRTypeSRV,
+		"name": "_ldap._tcp",
+		"srv":  []any{map[string]any{"priority": 0, "weight": 5, "port": 389, "target": "dc2.example.com"}},
+	})
+	d.SetId("example.com/SRV/_ldap._tcp")
+
+	record, err = loadZoneRecord(d, d, meta)
+	require.NoError(t, err)
+
+	if assert.NotNil(t, record) {
+		assert.Equal(t, "dc2.example.com", record.Data4)
+	}
+
+	// Not decidable, when the data of one of them was changed
+	require.NoError(t, d.Set("srv", []any{map[string]any{"priority": 0, "weight": 5, "port": 389,
+		"target": "dc3.example.com"}}))
+
+	diags = resourceZoneRecordRead(context.Background(), d, meta)
+	require.True(t, diags.HasError())
+	assert.Contains(t, diags[0].Summary, ErrNonUniqueRR.Error())
+	assert.Equal(t, "example.com/SRV/_ldap._tcp", d.Id())
+
+	// Removed outside of Terraform
+	d.SetId("example.com/MX/@")
+
+	diags = resourceZoneRecordRead(context.Background(), d, meta)
+	require.False(t, diags.HasError())
+	require.Len(t, diags, 1)
+	assert.Equal(t, diag.Warning, diags[0].Severity)
+	assert.Empty(t, d.Id())
+}
+
+func TestExpandZoneRecord_ReverseZone(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceZoneRecord().Schema, map[string]any{
+		"zone": "2.0.192.in-addr.arpa",
+		"type": rr.RRTypePTR,
+		"name": "50",
+		"ptr":  []any{map[string]any{"target": "host.example.com"}},
+	})
+
+	record, err := expandZoneRecord(d)
+	require.NoError(t, err)
+	assert.Equal(t, rr.InfraTypeV4ReverseZone, record.InfraType)
+	assert.Equal(t, "2.0.192.in-addr.arpa", record.InfraFQDN)
+	assert.Equal(t, "50.2.0.192.in-addr.arpa", record.Owner)
+	assert.Equal(t, "host.example.com", record.Data1)
+}
+
+func TestExpandZoneRecord_AAAA(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceZoneRecord().Schema, map[string]any{
+		"zone": "example.com",
+		"type": rr.RRTypeAAAA,
+		"name": "web",
+		"aaaa": []any{map[string]any{"address": "2001:DB8:0:0::10"}},
+	})
+
+	record, err := expandZoneRecord(d)
+	require.NoError(t, err)
+	assert.Equal(t, rr.RRTypeAAAA, record.RRType)
+	assert.Equal(t, "web.example.com", record.Owner)
+	assert.Equal(t, "2001:db8::10", record.Data1)
+
+	values, err := flattenDNSRecordBlocks(record, zoneRecordTypes)
+	require.NoError(t, err)
+	assert.Equal(t, []any{map[string]any{"address": "2001:db8::10"}}, values["aaaa"])
+	assert.Equal(t, []any{}, values["mx"])
+
+	// qip_dns_record has no aaaa block
+	_, ok := resourceDNSRecord().Schema["aaaa"]
+	assert.False(t, ok)
+}
+
+func TestAccResourceZoneRecord(t *testing.T) {
+	zone := getRequiredEnv(t, "QIP_TEST_ACC_ZONE")
+	name := getRandomName("_terraform-qip-zone")
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+					resource "qip_zone_record" "test" {
+						zone = "` + zone + `"
+						type = "TXT"
+						name = "` + name + `"
+						ttl  = 300
+
+						txt {
+							value = "verification=terraform"
+						}
+					}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("qip_zone_record.test", "txt.0.value", "verification=terraform"),
+					resource.TestCheckResourceAttr("qip_zone_record.test", "ttl", "300"),
+					resource.TestMatchResourceAttr("qip_zone_record.test", "fqdn", stringRe(name+"."+zone)),
+				),
+			},
+			{
+				ResourceName:      "qip_zone_record.test",
+				ImportState:       true,
+				ImportStateId:     zone + "/TXT/" + name,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
@@ -19,15 +19,24 @@ limitations under the License.
 package provider
 
 import (
-	"net"
+	"regexp"
+	"strconv"
+	"strings"
 
 	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/rr"
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/v4address"
 )
 
 const MaxObjectDescriptionLength = 32
 
+// ttlRe matches a TTL in seconds, -1 selects the default TTL of the zone.
+var ttlRe = regexp.MustCompile(`^(-1|[0-9]+)$`)
+
 func schemaV4Address(forData bool) map[string]*schema.Schema {
 	s := map[string]*schema.Schema{
 		"address": {
@@ -68,14 +77,56 @@ func schemaV4Address(forData bool) map[string]*schema.Schema {
 			Optional:    !forData,
 			Computed:    true,
 		},
+		"publish_ptr": {
+			Description: "Publishing of the PTR record QIP generates for the address in its reverse zone.",
+			Type:        schema.TypeString,
+			Optional:    !forData,
+			Computed:    true,
+		},
+		"ptr_ttl": {
+			Description: "TTL in seconds of the PTR record QIP generates for the address, `-1` uses the default TTL " +
+				"of the reverse zone.",
+			Type:     schema.TypeString,
+			Optional: !forData,
+			Computed: true,
+		},
+		"expires_at": {
+			Description: "Expiry date of the address object as RFC3339 timestamp (e.g. `2024-12-31T00:00:00Z`). " +
+				"QIP only stores the day in UTC.",
+			Type:     schema.TypeString,
+			Optional: !forData,
+			Computed: forData,
+		},
 	}
 
 	if !forData {
 		// Add schema entries only for the resource
 		s["address"].ValidateDiagFunc = validateIPV4Address
 		s["subnet"].ValidateDiagFunc = validateIPV4Address
+		s["expires_at"].ValidateDiagFunc = validation.ToDiagFunc(validation.IsRFC3339Time)
+		s["name"].ValidateDiagFunc = validateHostname(false)
+		s["domain_name"].ValidateDiagFunc = validateDomainName
+		s["description"].ValidateDiagFunc = validateDescription
+		s["description"].Description += " QIP stores at most " + strconv.Itoa(MaxObjectDescriptionLength) +
+			" characters, differences after the truncated part are ignored."
+		s["publish_ptr"].ValidateDiagFunc = validation.ToDiagFunc(validation.StringInSlice(rr.Publishings, false))
+		s["publish_ptr"].Description += " One of `" + strings.Join(rr.Publishings, "`, `") + "`, " +
+			"the default of QIP is kept when not set."
+		s["ptr_ttl"].ValidateDiagFunc = validation.ToDiagFunc(validation.StringMatch(ttlRe, "must be a TTL in seconds or -1"))
+
+		s["address"].Description = "IPv4 address. Changing the address moves the object including its RRs to the new address."
+		s["subnet"].Description = "Subnet of the IPv4 address. Changing the subnet moves the object, " +
+			"a free address is selected when `address` is not configured."
+
+		for key, field := range v4AddressFields {
+			s[key].DiffSuppressFunc = suppressNormalizedDiff(field.normalize)
+		}
+
+		s["name"].DiffSuppressFunc = suppressRelativeHostnameDiff
+		s["name"].Description = "Hostname for the address, relative to `domain_name`. " +
+			"Names are stored in lower case, internationalized names as punycode."
 
-		s["description"].DiffSuppressFunc = func(_, oldValue, newValue string, _ *schema.ResourceData) bool {
+		s["description"].DiffSuppressFunc = func(k, oldValue, newValue string, d *schema.ResourceData) bool {
 			// Do not change a value when the description length is larger than MaxObjectDescriptionLength
 			// and the non exceeding characters are equal.
 			if len(newValue) > MaxObjectDescriptionLength {
@@ -85,9 +136,11 @@ func schemaV4Address(forData bool) map[string]*schema.Schema {
 				}
 			}
 
-			return false
+			return suppressNormalizedDiff(normalizeValue)(k, oldValue, newValue, d)
 		}
 
+		s["duplicate_check"] = schemaDuplicateCheck(DuplicateCheckName, DuplicateCheckFQDN)
+
 		s["subnet_range_start"] = &schema.Schema{
 			Description:      "Starting address of a range to select a free IPv4 address from. Will be passed to QIP.",
 			Type:             schema.TypeString,
@@ -101,6 +154,45 @@ func schemaV4Address(forData bool) map[string]*schema.Schema {
 			Optional:         true,
 			ValidateDiagFunc: validateIPV4Address,
 		}
+
+		s["allocation"] = &schema.Schema{
+			Description: "Controls how a free IPv4 address is selected from the subnet, when no `address` is set. " +
+				"Candidate ranges are computed from the subnet mask and limited by `subnet_range_start` and `subnet_range_end`.",
+			Type:     schema.TypeList,
+			Optional: true,
+			MaxItems: 1,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"strategy": {
+						Description: "How to pick a free address. `default` lets QIP pick, `lowest` and `highest` pick the " +
+							"lowest or highest free address, `random` picks any free address and `offset` picks the lowest " +
+							"free address at or after the first host following `offset` hosts.",
+						Type:     schema.TypeString,
+						Optional: true,
+						Default:  v4address.AllocationDefault,
+						ValidateDiagFunc: validation.ToDiagFunc(
+							validation.StringInSlice(v4address.AllocationStrategies, false)),
+					},
+					"offset": {
+						Description: "Number of host addresses at the start of the subnet that are never used. " +
+							"The offset limits the candidates of every strategy, e.g. `highest` still picks the highest free address.",
+						Type:             schema.TypeInt,
+						Optional:         true,
+						ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(0)),
+					},
+					"exclude": {
+						Description: "Addresses that must not be selected. " +
+							"Single addresses, ranges (e.g. `192.0.2.10-192.0.2.20`) or CIDR networks.",
+						Type:     schema.TypeList,
+						Optional: true,
+						Elem: &schema.Schema{
+							Type:             schema.TypeString,
+							ValidateDiagFunc: validateIPV4AddressRange,
+						},
+					},
+				},
+			},
+		}
 	}
 
 	return s
@@ -112,14 +204,58 @@ func validateIPV4Address(value interface{}, _ cty.Path) diag.Diagnostics {
 		return diag.Errorf("value is not a string")
 	}
 
-	ip := net.ParseIP(address)
-	if ip == nil || ip.To4() == nil {
+	if !isIPv4Address(address) {
 		return diag.Errorf("value is not an IPv4 address")
 	}
 
 	return nil
 }
 
+func validateIPV4AddressRange(value interface{}, _ cty.Path) diag.Diagnostics {
+	addressRange, ok := value.(string)
+	if !ok {
+		return diag.Errorf("value is not a string")
+	}
+
+	if err := v4address.ValidateAddressRange(addressRange); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func validateMACAddress(value interface{}, _ cty.Path) diag.Diagnostics {
+	mac, ok := value.(string)
+	if !ok {
+		return diag.Errorf("value is not a string")
+	}
+
+	if _, err := v4address.NormalizeMACAddress(mac); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func stateMACAddress(value interface{}) string {
+	mac, _ := value.(string)
+
+	return normalizeMACAddress(mac)
+}
+
+func validatePowerOfTwo(value interface{}, _ cty.Path) diag.Diagnostics {
+	number, ok := value.(int)
+	if !ok {
+		return diag.Errorf("value is not a number")
+	}
+
+	if number < 1 || number&(number-1) != 0 {
+		return diag.Errorf("value is not a power of two")
+	}
+
+	return nil
+}
+
 func ifSet(condition bool, value any) any {
 	if condition {
 		return value
@@ -127,3 +263,30 @@ func ifSet(condition bool, value any) any {
 
 	return nil
 }
+
+// schemaRecordTTL returns the ttl attribute of a record.
+func schemaRecordTTL() *schema.Schema {
+	return &schema.Schema{
+		Description: "TTL of the record in seconds, `-1` uses the default TTL of the zone. " +
+			"`0` is not supported, QIP does not store it.",
+		Type:     schema.TypeInt,
+		Optional: true,
+		Default:  rr.TTLDefault,
+		// A TTL of 0 is omitted when the record is sent to QIP
+		ValidateDiagFunc: validation.ToDiagFunc(validation.Any(
+			validation.IntInSlice([]int{rr.TTLDefault}),
+			validation.IntAtLeast(1),
+		)),
+	}
+}
+
+// schemaRecordPublishing returns the publishing attribute of a record.
+func schemaRecordPublishing() *schema.Schema {
+	return &schema.Schema{
+		Description:      "Publishing of the record in DNS, one of `" + strings.Join(rr.Publishings, "`, `") + "`.",
+		Type:             schema.TypeString,
+		Optional:         true,
+		Default:          rr.PublishingAlways,
+		ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(rr.Publishings, false)),
+	}
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/dnsname"
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/domain"
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/objectclass"
+)
+
+var ErrUnknownValue = errors.New("value is not known by QIP")
+
+// validateHostname returns a validator for hostnames relative to their domain (e.g. `host` or `host.sub`).
+//
+// Records additionally allow underscores and a leading wildcard label (e.g. `*.entry-extra`).
+func validateHostname(forRecord bool) schema.SchemaValidateDiagFunc {
+	validate := dnsname.ValidateHostname
+	if forRecord {
+		validate = dnsname.ValidateOwner
+	}
+
+	return func(value interface{}, path cty.Path) diag.Diagnostics {
+		name, ok := value.(string)
+		if !ok {
+			return diag.Errorf("value is not a string")
+		}
+
+		if err := validate(name); err != nil {
+			return attributeError(path, err.Error())
+		}
+
+		return nil
+	}
+}
+
+func validateDomainName(value interface{}, path cty.Path) diag.Diagnostics {
+	return validateHostname(false)(value, path)
+}
+
+// validateDescription warns when the description will be truncated by QIP.
+func validateDescription(value interface{}, path cty.Path) diag.Diagnostics {
+	description, ok := value.(string)
+	if !ok {
+		return diag.Errorf("value is not a string")
+	}
+
+	if len(description) <= MaxObjectDescriptionLength {
+		return nil
+	}
+
+	return diag.Diagnostics{
+		{
+			Severity: diag.Warning,
+			Summary:  "Description will be truncated",
+			Detail: fmt.Sprintf("QIP only stores %d characters of the description, it will be stored as %q.",
+				MaxObjectDescriptionLength, description[:MaxObjectDescriptionLength]),
+			AttributePath: path,
+		},
+	}
+}
+
+func attributeError(path cty.Path, detail string) diag.Diagnostics {
+	return diag.Diagnostics{
+		{
+			Severity:      diag.Error,
+			Summary:       "Invalid value",
+			Detail:        detail,
+			AttributePath: path,
+		},
+	}
+}
+
+// serverConstraints caches the object classes and domains known by QIP.
+type serverConstraints struct {
+	mu            sync.Mutex
+	objectClasses map[string]string
+	domains       map[string]bool
+}
+
+// objectClassKnown checks if QIP knows the object class, the list is loaded on first use.
+func (c *terraformClient) objectClassKnown(name string) (bool, []string, error) {
+	c.constraints.mu.Lock()
+	defer c.constraints.mu.Unlock()
+
+	if c.constraints.objectClasses == nil {
+		classes, err := objectclass.List(c.QIPClient)
+		if err != nil {
+			return false, nil, fmt.Errorf("could not load object classes: %w", err)
+		}
+
+		c.constraints.objectClasses = make(map[string]string, len(classes))
+		for _, class := range classes {
+			c.constraints.objectClasses[strings.ToLower(class.Name)] = class.Name
+		}
+	}
+
+	_, known := c.constraints.objectClasses[strings.ToLower(name)]
+
+	return known, sortedValues(c.constraints.objectClasses), nil
+}
+
+// domainKnown checks if QIP manages the domain, the list is loaded on first use.
+func (c *terraformClient) domainKnown(name string) (bool, error) {
+	c.constraints.mu.Lock()
+	defer c.constraints.mu.Unlock()
+
+	if c.constraints.domains == nil {
+		domains, err := domain.List(c.QIPClient)
+		if err != nil {
+			return false, fmt.Errorf("could not load domains: %w", err)
+		}
+
+		c.constraints.domains = make(map[string]bool, len(domains))
+		for _, d := range domains {
+			c.constraints.domains[normalizeHostname(d.DomainName)] = true
+		}
+	}
+
+	return c.constraints.domains[normalizeHostname(name)], nil
+}
+
+func sortedValues(m map[string]string) []string {
+	values := make([]string, 0, len(m))
+	for _, value := range m {
+		values = append(values, value)
+	}
+
+	sort.Strings(values)
+
+	return values
+}
+
+// customizeDiffServerConstraints returns a CustomizeDiffFunc, that checks object_class and domain_name
+// against the values known by QIP, when validate_with_server is enabled for the provider.
+//
+// Only the listed attributes are checked, they must exist in the schema.
+func customizeDiffServerConstraints(attributes ...string) schema.CustomizeDiffFunc {
+	return func(_ context.Context, d *schema.ResourceDiff, meta any) error {
+		client, ok := meta.(*terraformClient)
+		if !ok || !client.validateWithServer {
+			return nil
+		}
+
+		for _, attribute := range attributes {
+			if !d.NewValueKnown(attribute) || (d.Id() != "" && !d.HasChange(attribute)) {
+				continue
+			}
+
+			value, _ := d.Get(attribute).(string)
+			if normalizeValue(value) == "" {
+				continue
+			}
+
+			err := checkServerConstraint(client, attribute, value)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+func checkServerConstraint(client *terraformClient, attribute, value string) error {
+	switch attribute {
+	case "object_class":
+		known, classes, err := client.objectClassKnown(value)
+		if err != nil {
+			return err
+		} else if !known {
+			return fmt.Errorf("%w: %s: object class %q is not known by QIP, known classes: %s",
+				ErrUnknownValue, attribute, value, strings.Join(classes, ", "))
+		}
+	case "domain_name":
+		known, err := client.domainKnown(value)
+		if err != nil {
+			return err
+		} else if !known {
+			return fmt.Errorf("%w: %s: domain %q is not managed by QIP", ErrUnknownValue, attribute, value)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/test"
+)
+
+func TestValidateHostname(t *testing.T) {
+	for _, name := range []string{"host", "host-01", "Host.sub", "a"} {
+		assert.Empty(t, validateHostname(false)(name, nil), name)
+	}
+
+	for _, name := range []string{"", "-host", "host-", "host_name", "host..sub", "*.host", strings.Repeat("a", 64)} {
+		diags := validateHostname(false)(name, nil)
+		if assert.Len(t, diags, 1, name) {
+			assert.Equal(t, diag.Error, diags[0].Severity)
+		}
+	}
+
+	for _, name := range []string{"*.entry-extra", "_sip._tcp", "entry"} {
+		assert.Empty(t, validateHostname(true)(name, nil), name)
+	}
+}
+
+func TestValidateDomainName(t *testing.T) {
+	assert.Empty(t, validateDomainName("corp.example.com", nil))
+	assert.Empty(t, validateDomainName("corp.example.com.", nil))
+	assert.NotEmpty(t, validateDomainName("corp example.com", nil))
+}
+
+func TestValidateDescription(t *testing.T) {
+	assert.Empty(t, validateDescription("short", nil))
+
+	diags := validateDescription("a description that is longer than QIP allows", nil)
+	if assert.Len(t, diags, 1) {
+		assert.Equal(t, diag.Warning, diags[0].Severity)
+	}
+}
+
+func TestResourceV4AddressValidate_AttributePaths(t *testing.T) {
+	diags := resourceV4Address().Validate(terraform.NewResourceConfigRaw(map[string]any{
+		"subnet":      "192.0.2.0",
+		"name":        "host_name",
+		"description": strings.Repeat("a", MaxObjectDescriptionLength+1),
+	}))
+
+	if assert.Len(t, diags, 2) {
+		for _, d := range diags {
+			assert.NotEmpty(t, d.AttributePath, d.Summary)
+		}
+	}
+}
+
+func TestSchemaV4Address_DescriptionTruncation(t *testing.T) {
+	suppress := schemaV4Address(false)["description"].DiffSuppressFunc
+	long := strings.Repeat("a", MaxObjectDescriptionLength+1)
+
+	assert.True(t, suppress("description", long[:MaxObjectDescriptionLength], long, nil))
+	assert.False(t, suppress("description", long[:MaxObjectDescriptionLength-1], long, nil))
+	assert.True(t, suppress("description", "None", "", nil))
+}
+
+func TestSchemaRecordTTL(t *testing.T) {
+	validate := schemaRecordTTL().ValidateDiagFunc
+
+	for _, ttl := range []int{-1, 1, 3600} {
+		assert.False(t, validate(ttl, nil).HasError(), ttl)
+	}
+
+	// 0 is omitted in the request, the plan would never converge
+	for _, ttl := range []int{-2, 0} {
+		assert.True(t, validate(ttl, nil).HasError(), ttl)
+	}
+}
+
+func TestCheckServerConstraint(t *testing.T) {
+	c, cleanup := test.GetTestClient(t)
+	defer cleanup()
+
+	baseURL := test.QIPServer + "/api/v1/" + test.QIPOrg
+
+	httpmock.RegisterResponder("GET", baseURL+"/objectclass.json",
+		httpmock.NewStringResponder(200, `{"list":[{"name":"Server"},{"name":"Virtualized Server"}]}`))
+	httpmock.RegisterResponder("GET", baseURL+"/domain.json",
+		httpmock.NewStringResponder(200, `{"list":[{"domainName":"corp.example.com"}]}`))
+
+	client := &terraformClient{QIPClient: c, validateWithServer: true}
+
+	require.NoError(t, checkServerConstraint(client, "object_class", "virtualized server"))
+	require.NoError(t, checkServerConstraint(client, "domain_name", "Corp.Example.com."))
+
+	err := checkServerConstraint(client, "object_class", "Toaster")
+	require.ErrorIs(t, err, ErrUnknownValue)
+	assert.Contains(t, err.Error(), "object_class: ")
+	assert.Contains(t, err.Error(), "Virtualized Server")
+
+	err = checkServerConstraint(client, "domain_name", "other.example.com")
+	require.ErrorIs(t, err, ErrUnknownValue)
+
+	// Lists are cached
+	info := httpmock.GetCallCountInfo()
+	assert.Equal(t, 1, info["GET "+baseURL+"/objectclass.json"])
+	assert.Equal(t, 1, info["GET "+baseURL+"/domain.json"])
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dnsname normalizes and validates DNS names used for objects and records.
+//
+// The canonical form of a name is lower case ASCII, internationalized labels are encoded
+// as punycode (IDNA), and it has no trailing dot.
+package dnsname
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// Limits of DNS names, see RFC 1035.
+const (
+	MaxLabelLength = 63
+	MaxNameLength  = 253
+)
+
+// Wildcard is the label matching any name, it is only allowed as the first label of a record owner.
+const Wildcard = "*"
+
+var (
+	ErrEmpty           = errors.New("name must not be empty")
+	ErrTooLong         = errors.New("name is too long")
+	ErrInvalidLabel    = errors.New("label is not valid")
+	ErrInvalidWildcard = errors.New("wildcard is only allowed as the first label")
+)
+
+// profile converts names to ASCII like a lookup, but leaves checking the characters to this package,
+// so wildcards and underscores of record owners are kept.
+var profile = idna.New(
+	idna.MapForLookup(),
+	idna.Transitional(false),
+	idna.StrictDomainName(false),
+)
+
+// ToASCII returns the canonical form of a name, or an error when it can not be encoded.
+func ToASCII(name string) (string, error) {
+	name = strings.TrimSuffix(strings.TrimSpace(name), ".")
+	if name == "" {
+		return "", ErrEmpty
+	}
+
+	ascii, err := profile.ToASCII(name)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidLabel, err)
+	}
+
+	return ascii, nil
+}
+
+// Canonical returns the canonical form of a name, names that can not be encoded are only lower cased.
+func Canonical(name string) string {
+	if ascii, err := ToASCII(name); err == nil {
+		return ascii
+	}
+
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), ".")
+}
+
+// Equal compares two names in their canonical form.
+func Equal(a, b string) bool {
+	return Canonical(a) == Canonical(b)
+}
+
+// Join returns the canonical FQDN of a name within the domain.
+//
+// Names that are already fully qualified within the domain, or end with a dot, are not extended.
+func Join(name, domain string) string {
+	absolute := strings.HasSuffix(strings.TrimSpace(name), ".")
+	name = Canonical(name)
+	domain = Canonical(domain)
+
+	if absolute || domain == "" || name == domain || strings.HasSuffix(name, "."+domain) {
+		return name
+	}
+
+	if name == "" {
+		return domain
+	}
+
+	return name + "." + domain
+}
+
+// Relative returns the canonical name relative to the domain, it is the inverse of Join.
+func Relative(name, domain string) string {
+	name = Canonical(name)
+	domain = Canonical(domain)
+
+	if domain != "" {
+		if relative, ok := strings.CutSuffix(name, "."+domain); ok {
+			return relative
+		}
+	}
+
+	return name
+}
+
+// ValidateHostname checks a hostname according to RFC 1123, labels only contain letters, digits and hyphens.
+func ValidateHostname(name string) error {
+	return validate(name, false)
+}
+
+// ValidateOwner checks the owner of a record, which additionally allows underscores in labels (e.g. `_sip._tcp`)
+// and a wildcard as first label (e.g. `*.example.com`).
+func ValidateOwner(name string) error {
+	return validate(name, true)
+}
+
+func validate(name string, owner bool) error {
+	ascii, err := ToASCII(name)
+	if err != nil {
+		return err
+	}
+
+	if len(ascii) > MaxNameLength {
+		return fmt.Errorf("%w: %q is longer than %d characters", ErrTooLong, name, MaxNameLength)
+	}
+
+	for i, label := range strings.Split(ascii, ".") {
+		if label == Wildcard && owner {
+			if i > 0 {
+				return fmt.Errorf("%w: %q", ErrInvalidWildcard, name)
+			}
+
+			continue
+		}
+
+		err = validateLabel(label, owner)
+		if err != nil {
+			return fmt.Errorf("%w of %q", err, name)
+		}
+	}
+
+	return nil
+}
+
+func validateLabel(label string, allowUnderscore bool) error {
+	if label == "" || len(label) > MaxLabelLength {
+		return fmt.Errorf("%w: %q must have 1 to %d characters", ErrInvalidLabel, label, MaxLabelLength)
+	}
+
+	if label[0] == '-' || label[len(label)-1] == '-' {
+		return fmt.Errorf("%w: %q must not start or end with a hyphen", ErrInvalidLabel, label)
+	}
+
+	for _, c := range label {
+		switch {
+		case c >= 'a' && c <= 'z', c >= '0' && c <= '9', c == '-':
+		case c == '_' && allowUnderscore:
+		default:
+			return fmt.Errorf("%w: %q contains the invalid character %q", ErrInvalidLabel, label, c)
+		}
+	}
+
+	return nil
+}
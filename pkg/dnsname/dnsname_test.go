@@ -0,0 +1,75 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dnsname_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/dnsname"
+)
+
+func TestCanonical(t *testing.T) {
+	tests := map[string]string{
+		"Host.Example.COM.":  "host.example.com",
+		" host ":             "host",
+		"Müller.example.com": "xn--mller-kva.example.com",
+		"*.Example.com":      "*.example.com",
+		"_sip._tcp.example":  "_sip._tcp.example",
+	}
+
+	for name, expected := range tests {
+		assert.Equal(t, expected, dnsname.Canonical(name), name)
+	}
+
+	assert.True(t, dnsname.Equal("xn--mller-kva.example.com.", "MÜLLER.example.com"))
+}
+
+func TestJoin(t *testing.T) {
+	assert.Equal(t, "host.corp.example.com", dnsname.Join("Host", "corp.example.com."))
+	assert.Equal(t, "host.corp.example.com", dnsname.Join("host.corp.example.com", "corp.example.com"))
+	assert.Equal(t, "host.other.example", dnsname.Join("host.other.example.", "corp.example.com"))
+	assert.Equal(t, "host", dnsname.Join("host", ""))
+
+	assert.Equal(t, "host", dnsname.Relative("Host.Corp.example.com", "corp.example.com"))
+	assert.Equal(t, "host.other.example", dnsname.Relative("host.other.example", "corp.example.com"))
+}
+
+func TestValidateHostname(t *testing.T) {
+	for _, name := range []string{"host", "host-01.example.com", "Müller", strings.Repeat("a", 63)} {
+		require.NoError(t, dnsname.ValidateHostname(name), name)
+	}
+
+	for _, name := range []string{"", "-host", "host-", "host_name", "host..example", "*.example.com",
+		strings.Repeat("a", 64), strings.Repeat("a.", 127) + "a"} {
+		require.Error(t, dnsname.ValidateHostname(name), name)
+	}
+}
+
+func TestValidateOwner(t *testing.T) {
+	for _, name := range []string{"*.entry-extra", "_sip._tcp.example.com", "entry"} {
+		require.NoError(t, dnsname.ValidateOwner(name), name)
+	}
+
+	require.ErrorIs(t, dnsname.ValidateOwner("entry.*.example.com"), dnsname.ErrInvalidWildcard)
+	require.ErrorIs(t, dnsname.ValidateOwner("entry extra"), dnsname.ErrInvalidLabel)
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package domain
+
+import (
+	"fmt"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip"
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/rest"
+)
+
+//go:generate go run github.com/Vitesco-Technologies/terraform-provider-qip/pkg/utils/qip_type -type Domain -package domain
+
+type listResult struct {
+	List []*Domain
+}
+
+// List returns all DNS domains managed by the organization.
+func List(client *qip.Client) ([]*Domain, error) {
+	request, err := rest.NewRequest("GET", client.APITenantURL("domain.json"), nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not build get request: %w", err)
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("could not list Domain: %w", err)
+	}
+
+	var results listResult
+
+	err = rest.UnmarshalResponse(response, &results)
+	if err != nil {
+		return nil, fmt.Errorf("could not unmarshal JSON result: %w", err)
+	}
+
+	return results.List, nil
+}
@@ -20,8 +20,8 @@ package rr
 
 import (
 	"fmt"
-	"net/url"
 
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/dnsname"
 	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip"
 	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/rest"
 )
@@ -29,13 +29,15 @@ import (
 //go:generate go run github.com/Vitesco-Technologies/terraform-provider-qip/pkg/utils/qip_type -type RR -package rr
 
 // Equal checks if two RR are equal by checking the identifying attributes.
+//
+// Owner and InfraFQDN are compared in their canonical form, see dnsname.Canonical.
 func (record *RR) Equal(otherRecord *RR) bool {
-	return record.Owner == otherRecord.Owner &&
+	return dnsname.Equal(record.Owner, otherRecord.Owner) &&
 		record.ClassType == otherRecord.ClassType &&
 		record.RRType == otherRecord.RRType &&
 		record.InfraType == otherRecord.InfraType &&
 		record.InfraAddr == otherRecord.InfraAddr &&
-		record.InfraFQDN == otherRecord.InfraFQDN
+		dnsname.Equal(record.InfraFQDN, otherRecord.InfraFQDN)
 }
 
 // DeleteInfo is a subset of RR to delete a RR (singleDelete is added for deletion).
@@ -48,19 +50,40 @@ type DeleteInfo struct {
 	SingleDelete bool   `json:"singleDelete"`
 }
 
+// Values for RR.RRType.
+const (
+	RRTypeA     = "A"
+	RRTypeAAAA  = "AAAA"
+	RRTypeCNAME = "CNAME"
+	RRTypeMX    = "MX"
+	RRTypePTR   = "PTR"
+	RRTypeSRV   = "SRV"
+	RRTypeTXT   = "TXT"
+)
+
+// Values for RR.Publishing, controlling in which DNS views QIP publishes the record.
 const (
-	PublishingAlways = "ALWAYS"
+	PublishingAlways   = "ALWAYS"
+	PublishingNever    = "NEVER"
+	PublishingInternal = "INTERNAL"
+	PublishingExternal = "EXTERNAL"
+)
+
+// Publishings lists all values for RR.Publishing.
+var Publishings = []string{PublishingAlways, PublishingNever, PublishingInternal, PublishingExternal}
 
-	InfraTypeObject = "OBJECT"
+// TTLDefault is the RR.TTL to use the default TTL of the zone.
+const TTLDefault = -1
 
-	/* Unused other values for InfraType.
+// Values for RR.InfraType, see Infra.
+const (
+	InfraTypeObject        = "OBJECT"
 	InfraTypeV6Address     = "V6ADDRESS"
 	InfraTypeZone          = "ZONE"
 	InfraTypeV4ReverseZone = "V4REVERSEZONE"
 	InfraTypeV6ReverseZone = "V6REVERSEZONE"
 	InfraTypeNode          = "NODE"
 	InfraTypeAll           = "ALL"
-	*/
 )
 
 type loadResult struct {
@@ -71,44 +94,54 @@ type loadResult struct {
 //
 // Owner is the respective FQDN for the DNS entry.
 func NewAForObject(owner, address string) *RR {
-	return &RR{
-		Owner:                   owner,
-		ClassType:               "IN",
-		RRType:                  "A",
-		Data1:                   address,
-		InfraType:               InfraTypeObject,
-		InfraAddr:               address,
-		Publishing:              PublishingAlways,
-		TTL:                     -1,
-		IsCreatingReverseZoneRR: false,
-		IsDefaultRR:             false,
-	}
+	return NewForObject(owner, RRTypeA, address, address)
+}
+
+// LoadOptions change which records are returned by the load functions.
+type LoadOptions struct {
+	// IncludeTombstoned returns deleted records, that are still kept by QIP as a tombstone.
+	IncludeTombstoned bool
+	// IncludeDefault returns the default records QIP generates for objects.
+	IncludeDefault bool
+}
+
+// IsTombstoned checks if the record is only kept as a tombstone of a deleted record.
+//
+// Unlike objects, QIP returns the flag of records as a number.
+func (record *RR) IsTombstoned() bool {
+	return record.Tombstoned != 0
 }
 
+// LoadAllForObject returns all non default records of the object, tombstoned records are left out.
 func LoadAllForObject(client *qip.Client, address string) ([]*RR, error) {
-	query := url.Values{}
-	query.Set("address", address)
-	query.Set("type", InfraTypeObject)
-	query.Set("getDefaultRRs", "false")
+	return LoadAllForObjectWithOptions(client, address, nil)
+}
 
-	request, err := rest.NewRequest("GET", client.APITenantURL("rr.json")+"?"+query.Encode(), nil)
-	if err != nil {
-		return nil, fmt.Errorf("could not build get request: %w", err)
-	}
+// LoadAllForObjectWithOptions returns all non default records of the object.
+func LoadAllForObjectWithOptions(client *qip.Client, address string, opts *LoadOptions) ([]*RR, error) {
+	return LoadAllForInfra(client, ForObject(address), opts)
+}
 
-	response, err := client.Do(request)
-	if err != nil {
-		return nil, fmt.Errorf("could not load RR: %w", err)
-	}
+// LoadAllForOwner returns all non default records with the owner (FQDN), regardless of the infrastructure.
+//
+// Tombstoned records are left out.
+func LoadAllForOwner(client *qip.Client, owner string) ([]*RR, error) {
+	return LoadAllForOwnerWithOptions(client, owner, nil)
+}
 
-	var results loadResult
+// LoadAllForOwnerWithOptions returns all records with the owner (FQDN), default records only when included.
+func LoadAllForOwnerWithOptions(client *qip.Client, owner string, opts *LoadOptions) ([]*RR, error) {
+	return Search(client, opts.query(&Query{Owner: owner}))
+}
 
-	err = rest.UnmarshalResponse(response, &results)
-	if err != nil {
-		return nil, fmt.Errorf("could not unmarshal JSON result: %w", err)
+// query applies the options to the query of Search.
+func (opts *LoadOptions) query(query *Query) *Query {
+	if opts != nil {
+		query.IncludeTombstoned = opts.IncludeTombstoned
+		query.IncludeDefault = opts.IncludeDefault
 	}
 
-	return results.List, nil
+	return query
 }
 
 func Create(client *qip.Client, rr *RR) error {
@@ -144,20 +177,14 @@ func Update(client *qip.Client, oldRR, newRR *RR) error {
 	return nil
 }
 
-// Delete will remove a RR from QIP in connection to the belonging object.
+// Delete will remove a RR from QIP in connection to the belonging infrastructure.
 //
-// Note: this copies values from an RR instance to DeleteInfo, so the API understands the deletion request.
+// Note: this copies values from an RR instance to DeleteInfo (see NewDeleteInfo), so the API understands the
+// deletion request.
 // Sending a simple RR objects yields a NullPointerException within the API.
 // This is not really well documented, you will notice the "singleDelete" attribute in the model, but not the example.
 func Delete(client *qip.Client, rr *RR) error {
-	deleteInfo := &DeleteInfo{
-		Owner:        rr.Owner,
-		RRType:       rr.RRType,
-		InfraType:    rr.InfraType,
-		InfraFQDN:    rr.InfraFQDN,
-		InfraAddr:    rr.InfraAddr,
-		SingleDelete: true,
-	}
+	deleteInfo := NewDeleteInfo(rr)
 
 	request, err := rest.NewRequest("DELETE", client.APITenantURL("rr"), deleteInfo)
 	if err != nil {
@@ -0,0 +1,66 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rr
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/dnsname"
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip"
+)
+
+var ErrCNAMEConflict = errors.New("a CNAME record can not share its owner with other records")
+
+// NewCNAMEForObject returns a RR for a CNAME record belonging to an object in QIP.
+//
+// Owner is the alias FQDN, target is the canonical FQDN the alias points to.
+func NewCNAMEForObject(owner, target, address string) *RR {
+	return NewForObject(owner, RRTypeCNAME, address, target)
+}
+
+// CheckConflict checks the records of the owner, before record is created.
+//
+// A CNAME can not be created when other records exist for the owner, and no other record can be created
+// when a CNAME exists. The record ignore is left out, e.g. when it is replaced by record.
+// Default records are included, so an object with the owner as FQDN is a conflict as well.
+func CheckConflict(client *qip.Client, record, ignore *RR) error {
+	records, err := LoadAllForOwnerWithOptions(client, record.Owner, &LoadOptions{IncludeDefault: true})
+	if err != nil {
+		var notFoundErr *qip.HTTPNotFoundError
+		if errors.As(err, &notFoundErr) {
+			return nil
+		}
+
+		return err
+	}
+
+	for _, other := range records {
+		if (ignore != nil && other.Equal(ignore)) || !dnsname.Equal(other.Owner, record.Owner) {
+			continue
+		}
+
+		if record.RRType == RRTypeCNAME || other.RRType == RRTypeCNAME {
+			return fmt.Errorf("%w: %s already has a %s record for %s", ErrCNAMEConflict,
+				record.Owner, other.RRType, other.InfraAddr)
+		}
+	}
+
+	return nil
+}
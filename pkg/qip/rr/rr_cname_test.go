@@ -0,0 +1,81 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rr_test
+
+import (
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/rr"
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/test"
+)
+
+func TestNewCNAMEForObject(t *testing.T) {
+	record := rr.NewCNAMEForObject("www.example.com", "host.example.com", "192.0.2.50")
+
+	assert.Equal(t, rr.RRTypeCNAME, record.RRType)
+	assert.Equal(t, "host.example.com", record.Data1)
+	assert.Equal(t, "192.0.2.50", record.InfraAddr)
+}
+
+func TestCheckConflict(t *testing.T) {
+	c, cleanup := test.GetTestClient(t)
+	defer cleanup()
+
+	url := test.QIPServer + "/api/v1/" + test.QIPOrg + "/rr.json"
+
+	httpmock.RegisterResponderWithQuery("GET", url, "owner=www.example.com&getDefaultRRs=true",
+		httpmock.NewStringResponder(200, `{"list":[{"owner":"www.example.com","classType":"IN","rrType":"A",
+			"data1":"192.0.2.50","infraType":"OBJECT","infraAddr":"192.0.2.50"}]}`))
+	httpmock.RegisterResponderWithQuery("GET", url, "owner=alias.example.com&getDefaultRRs=true",
+		httpmock.NewStringResponder(200, `{"list":[{"owner":"alias.example.com","classType":"IN","rrType":"CNAME",
+			"data1":"host.example.com","infraType":"OBJECT","infraAddr":"192.0.2.50"}]}`))
+	httpmock.RegisterResponderWithQuery("GET", url, "owner=host.example.com&getDefaultRRs=true",
+		httpmock.NewStringResponder(200, `{"list":[{"owner":"host.example.com","classType":"IN","rrType":"A",
+			"data1":"192.0.2.60","infraType":"OBJECT","infraAddr":"192.0.2.60","isDefaultRR":true}]}`))
+	httpmock.RegisterResponderWithQuery("GET", url, "owner=free.example.com&getDefaultRRs=true",
+		httpmock.NewStringResponder(404, ""))
+
+	// CNAME next to an A record
+	err := rr.CheckConflict(c, rr.NewCNAMEForObject("www.example.com", "host.example.com", "192.0.2.50"), nil)
+	require.ErrorIs(t, err, rr.ErrCNAMEConflict)
+
+	// Additional A record next to a CNAME
+	err = rr.CheckConflict(c, rr.NewAForObject("alias.example.com", "192.0.2.51"), nil)
+	require.ErrorIs(t, err, rr.ErrCNAMEConflict)
+
+	// Replacing the existing record
+	existing := rr.NewCNAMEForObject("alias.example.com", "host.example.com", "192.0.2.50")
+	err = rr.CheckConflict(c, rr.NewCNAMEForObject("alias.example.com", "other.example.com", "192.0.2.50"), existing)
+	require.NoError(t, err)
+
+	// A records can share the owner
+	err = rr.CheckConflict(c, rr.NewAForObject("www.example.com", "192.0.2.51"), nil)
+	require.NoError(t, err)
+
+	// Default A record of an existing object
+	err = rr.CheckConflict(c, rr.NewCNAMEForObject("host.example.com", "www.example.com", "192.0.2.50"), nil)
+	require.ErrorIs(t, err, rr.ErrCNAMEConflict)
+
+	err = rr.CheckConflict(c, rr.NewCNAMEForObject("free.example.com", "host.example.com", "192.0.2.50"), nil)
+	require.NoError(t, err)
+}
@@ -0,0 +1,270 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rr
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/dnsname"
+)
+
+// MaxTXTStringLength is the maximum length in bytes of a single character string of a TXT record.
+const MaxTXTStringLength = 255
+
+var (
+	ErrInvalidData     = errors.New("invalid record data")
+	ErrWrongRecordType = errors.New("wrong record type")
+)
+
+// MX is the data of a MX record, stored as Data1 (priority) and Data2 (host).
+type MX struct {
+	Priority int
+	Host     string
+}
+
+// SRV is the data of a SRV record, stored as Data1 (priority), Data2 (weight), Data3 (port) and Data4 (target).
+type SRV struct {
+	Priority int
+	Weight   int
+	Port     int
+	Target   string
+}
+
+// NewMX returns a RR for a MX record belonging to the infrastructure.
+func NewMX(infra Infra, owner string, mx MX) *RR {
+	return New(infra, owner, RRTypeMX, strconv.Itoa(mx.Priority), mx.Host)
+}
+
+// NewMXForObject returns a RR for a MX record belonging to an object in QIP.
+func NewMXForObject(owner, address string, mx MX) *RR {
+	return NewMX(ForObject(address), owner, mx)
+}
+
+// NewSRV returns a RR for a SRV record belonging to the infrastructure.
+//
+// Owner includes the service and protocol labels, e.g. _ldap._tcp.example.com.
+func NewSRV(infra Infra, owner string, srv SRV) *RR {
+	return New(infra, owner, RRTypeSRV,
+		strconv.Itoa(srv.Priority), strconv.Itoa(srv.Weight), strconv.Itoa(srv.Port), srv.Target)
+}
+
+// NewSRVForObject returns a RR for a SRV record belonging to an object in QIP.
+func NewSRVForObject(owner, address string, srv SRV) *RR {
+	return NewSRV(ForObject(address), owner, srv)
+}
+
+// NewTXT returns a RR for a TXT record belonging to the infrastructure.
+//
+// The text is split into character strings of MaxTXTStringLength and stored quoted in Data1, see QuoteTXT.
+func NewTXT(infra Infra, owner, text string) *RR {
+	return New(infra, owner, RRTypeTXT, QuoteTXT(SplitTXT(text)))
+}
+
+// NewTXTForObject returns a RR for a TXT record belonging to an object in QIP.
+func NewTXTForObject(owner, address, text string) *RR {
+	return NewTXT(ForObject(address), owner, text)
+}
+
+// NewPTR returns a RR for a PTR record belonging to the infrastructure.
+//
+// Owner is the reverse name (e.g. 50.2.0.192.in-addr.arpa), target the hostname it points to.
+func NewPTR(infra Infra, owner, target string) *RR {
+	return New(infra, owner, RRTypePTR, target)
+}
+
+// NewPTRForObject returns a RR for a PTR record belonging to an object in QIP.
+func NewPTRForObject(owner, target, address string) *RR {
+	return NewPTR(ForObject(address), owner, target)
+}
+
+// MX returns the data of a MX record.
+func (record *RR) MX() (*MX, error) {
+	if record.RRType != RRTypeMX {
+		return nil, fmt.Errorf("%w: %s is not %s", ErrWrongRecordType, record.RRType, RRTypeMX)
+	}
+
+	priority, err := parseUint16(record.Data1)
+	if err != nil {
+		return nil, fmt.Errorf("MX priority: %w", err)
+	}
+
+	return &MX{Priority: priority, Host: record.Data2}, nil
+}
+
+// SRV returns the data of a SRV record.
+func (record *RR) SRV() (*SRV, error) {
+	if record.RRType != RRTypeSRV {
+		return nil, fmt.Errorf("%w: %s is not %s", ErrWrongRecordType, record.RRType, RRTypeSRV)
+	}
+
+	var (
+		srv = &SRV{Target: record.Data4}
+		err error
+	)
+
+	for name, field := range map[string]struct {
+		value  string
+		target *int
+	}{
+		"priority": {record.Data1, &srv.Priority},
+		"weight":   {record.Data2, &srv.Weight},
+		"port":     {record.Data3, &srv.Port},
+	} {
+		*field.target, err = parseUint16(field.value)
+		if err != nil {
+			return nil, fmt.Errorf("SRV %s: %w", name, err)
+		}
+	}
+
+	return srv, nil
+}
+
+// TXT returns the text of a TXT record, with all character strings joined.
+func (record *RR) TXT() (string, error) {
+	if record.RRType != RRTypeTXT {
+		return "", fmt.Errorf("%w: %s is not %s", ErrWrongRecordType, record.RRType, RRTypeTXT)
+	}
+
+	parts, err := ParseTXT(record.Data1)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.Join(parts, ""), nil
+}
+
+// SplitTXT splits the text into character strings of at most MaxTXTStringLength bytes.
+//
+// UTF-8 characters are never split.
+func SplitTXT(text string) []string {
+	parts := []string{}
+
+	for len(text) > MaxTXTStringLength {
+		end := MaxTXTStringLength
+		for end > 0 && !utf8.RuneStart(text[end]) {
+			end--
+		}
+
+		parts = append(parts, text[:end])
+		text = text[end:]
+	}
+
+	return append(parts, text)
+}
+
+// QuoteTXT returns the character strings in zone file notation, e.g. "first" "second".
+func QuoteTXT(parts []string) string {
+	quoted := make([]string, len(parts))
+
+	for i, part := range parts {
+		part = strings.ReplaceAll(part, `\`, `\\`)
+		quoted[i] = `"` + strings.ReplaceAll(part, `"`, `\"`) + `"`
+	}
+
+	return strings.Join(quoted, " ")
+}
+
+// ParseTXT returns the character strings of TXT data in zone file notation.
+//
+// Data not starting with a quote is returned as a single string, as QIP might store it unquoted.
+func ParseTXT(data string) ([]string, error) {
+	data = strings.TrimSpace(data)
+	if !strings.HasPrefix(data, `"`) {
+		return []string{data}, nil
+	}
+
+	var (
+		parts   []string
+		current strings.Builder
+		quoted  bool
+		escaped bool
+	)
+
+	for _, char := range data {
+		switch {
+		case escaped:
+			current.WriteRune(char)
+
+			escaped = false
+		case quoted && char == '\\':
+			escaped = true
+		case char == '"':
+			if quoted {
+				parts = append(parts, current.String())
+				current.Reset()
+			}
+
+			quoted = !quoted
+		case quoted:
+			current.WriteRune(char)
+		case char != ' ' && char != '\t':
+			return nil, fmt.Errorf("%w: unexpected %q outside of quotes in TXT data", ErrInvalidData, char)
+		}
+	}
+
+	if quoted {
+		return nil, fmt.Errorf("%w: unterminated quote in TXT data", ErrInvalidData)
+	}
+
+	return parts, nil
+}
+
+// DataEqual checks if both records carry the same data, additionally to the identifying attributes of Equal.
+//
+// The data is compared in its canonical form, see canonicalData.
+func (record *RR) DataEqual(otherRecord *RR) bool {
+	return record.canonicalData() == otherRecord.canonicalData()
+}
+
+// canonicalData returns the data fields of the record in a form independent of how QIP stores them.
+//
+// TXT data is quoted in zone file notation (see ParseTXT), targets of CNAME, PTR, MX and SRV records are
+// canonical DNS names (see dnsname.Canonical).
+func (record *RR) canonicalData() [4]string {
+	data := [4]string{record.Data1, record.Data2, record.Data3, record.Data4}
+
+	switch record.RRType {
+	case RRTypeTXT:
+		if parts, err := ParseTXT(data[0]); err == nil {
+			data[0] = QuoteTXT(parts)
+		}
+	case RRTypeCNAME, RRTypePTR:
+		data[0] = dnsname.Canonical(data[0])
+	case RRTypeMX:
+		data[1] = dnsname.Canonical(data[1])
+	case RRTypeSRV:
+		data[3] = dnsname.Canonical(data[3])
+	}
+
+	return data
+}
+
+// parseUint16 parses a number in the range of 16 bit, used by priorities, weights and ports.
+func parseUint16(value string) (int, error) {
+	number, err := strconv.ParseUint(strings.TrimSpace(value), 10, 16)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q is not a number between 0 and 65535", ErrInvalidData, value)
+	}
+
+	return int(number), nil
+}
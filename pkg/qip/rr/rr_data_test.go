@@ -0,0 +1,130 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rr_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/rr"
+)
+
+func TestNewMXForObject(t *testing.T) {
+	record := rr.NewMXForObject("example.com", "192.0.2.50", rr.MX{Priority: 10, Host: "mail.example.com"})
+
+	assert.Equal(t, rr.RRTypeMX, record.RRType)
+	assert.Equal(t, "10", record.Data1)
+	assert.Equal(t, "mail.example.com", record.Data2)
+
+	mx, err := record.MX()
+	require.NoError(t, err)
+	assert.Equal(t, &rr.MX{Priority: 10, Host: "mail.example.com"}, mx)
+
+	_, err = record.SRV()
+	require.ErrorIs(t, err, rr.ErrWrongRecordType)
+
+	record.Data1 = "high"
+	_, err = record.MX()
+	require.ErrorIs(t, err, rr.ErrInvalidData)
+}
+
+func TestNewSRVForObject(t *testing.T) {
+	srv := rr.SRV{Priority: 0, Weight: 5, Port: 389, Target: "ldap.example.com"}
+	record := rr.NewSRVForObject("_ldap._tcp.example.com", "192.0.2.50", srv)
+
+	assert.Equal(t, rr.RRTypeSRV, record.RRType)
+	assert.Equal(t, []string{"0", "5", "389", "ldap.example.com"},
+		[]string{record.Data1, record.Data2, record.Data3, record.Data4})
+
+	parsed, err := record.SRV()
+	require.NoError(t, err)
+	assert.Equal(t, &srv, parsed)
+
+	record.Data3 = "70000"
+	_, err = record.SRV()
+	require.ErrorIs(t, err, rr.ErrInvalidData)
+}
+
+func TestNewTXTForObject(t *testing.T) {
+	record := rr.NewTXTForObject("example.com", "192.0.2.50", `v=spf1 "quoted" \ -all`)
+
+	assert.Equal(t, rr.RRTypeTXT, record.RRType)
+	assert.Equal(t, `"v=spf1 \"quoted\" \\ -all"`, record.Data1)
+
+	text, err := record.TXT()
+	require.NoError(t, err)
+	assert.Equal(t, `v=spf1 "quoted" \ -all`, text)
+
+	long := strings.Repeat("a", 300)
+	record = rr.NewTXTForObject("example.com", "192.0.2.50", long)
+	assert.Equal(t, `"`+strings.Repeat("a", 255)+`" "`+strings.Repeat("a", 45)+`"`, record.Data1)
+
+	text, err = record.TXT()
+	require.NoError(t, err)
+	assert.Equal(t, long, text)
+}
+
+func TestSplitTXT(t *testing.T) {
+	assert.Equal(t, []string{""}, rr.SplitTXT(""))
+	assert.Equal(t, []string{"short"}, rr.SplitTXT("short"))
+
+	// Multi byte characters are not split
+	parts := rr.SplitTXT(strings.Repeat("a", 254) + "ü" + "b")
+	assert.Equal(t, []string{strings.Repeat("a", 254), "üb"}, parts)
+}
+
+func TestParseTXT(t *testing.T) {
+	parts, err := rr.ParseTXT(`"first" "second"`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"first", "second"}, parts)
+
+	parts, err = rr.ParseTXT("unquoted text")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"unquoted text"}, parts)
+
+	_, err = rr.ParseTXT(`"unterminated`)
+	require.ErrorIs(t, err, rr.ErrInvalidData)
+
+	_, err = rr.ParseTXT(`"first" second`)
+	require.ErrorIs(t, err, rr.ErrInvalidData)
+}
+
+func TestDataEqual(t *testing.T) {
+	txt := rr.NewTXTForObject("host.example.com", "192.0.2.50", "text")
+	unquoted := rr.NewTXTForObject("host.example.com", "192.0.2.50", "text")
+	unquoted.Data1 = "text"
+
+	assert.True(t, txt.DataEqual(unquoted))
+	assert.False(t, txt.DataEqual(rr.NewTXTForObject("host.example.com", "192.0.2.50", "Text")))
+
+	mx := rr.NewMXForObject("example.com", "192.0.2.50", rr.MX{Priority: 10, Host: "Mail.Example.com."})
+	assert.True(t, mx.DataEqual(rr.NewMXForObject("example.com", "192.0.2.50", rr.MX{Priority: 10, Host: "mail.example.com"})))
+	assert.False(t, mx.DataEqual(rr.NewMXForObject("example.com", "192.0.2.50", rr.MX{Priority: 20, Host: "mail.example.com"})))
+
+	srv := rr.NewSRVForObject("_ldap._tcp.example.com", "192.0.2.50",
+		rr.SRV{Priority: 10, Weight: 5, Port: 389, Target: "LDAP.example.com."})
+	assert.True(t, srv.DataEqual(rr.NewSRVForObject("_ldap._tcp.example.com", "192.0.2.50",
+		rr.SRV{Priority: 10, Weight: 5, Port: 389, Target: "ldap.example.com"})))
+
+	cname := rr.NewCNAMEForObject("www.example.com", "Host.Example.com.", "192.0.2.50")
+	assert.True(t, cname.DataEqual(rr.NewCNAMEForObject("www.example.com", "host.example.com", "192.0.2.50")))
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rr
+
+import (
+	"fmt"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip"
+)
+
+// Change is an update of a record from Old to New.
+type Change struct {
+	Old *RR
+	New *RR
+}
+
+// Changes are the operations needed to turn a set of records into another, see Diff.
+type Changes struct {
+	Create []*RR
+	Update []Change
+	Delete []*RR
+}
+
+// Empty checks if there is nothing to change.
+func (changes *Changes) Empty() bool {
+	return len(changes.Create) == 0 && len(changes.Update) == 0 && len(changes.Delete) == 0
+}
+
+// Diff compares the current records in QIP with the desired records, records are paired using Equal.
+//
+// Records with the same data (see DataEqual) are paired first, so only the TTL or publishing is updated.
+// Remaining records of the same owner and type are updated with the desired data, the rest is created or deleted.
+func Diff(current, desired []*RR) *Changes {
+	changes := &Changes{}
+	used := make([]bool, len(current))
+	paired := make([]*RR, len(desired))
+
+	pair := func(match func(record, want *RR) bool) {
+		for i, want := range desired {
+			if paired[i] != nil {
+				continue
+			}
+
+			for j, record := range current {
+				if used[j] || !record.Equal(want) || !match(record, want) {
+					continue
+				}
+
+				used[j] = true
+				paired[i] = record
+
+				break
+			}
+		}
+	}
+
+	pair((*RR).DataEqual)
+	pair(func(_, _ *RR) bool { return true })
+
+	for i, want := range desired {
+		record := paired[i]
+		if record == nil {
+			changes.Create = append(changes.Create, want)
+
+			continue
+		}
+
+		if record.DataEqual(want) && record.TTL == want.TTL && record.Publishing == want.Publishing {
+			continue
+		}
+
+		updated := *record
+		updated.Data1 = want.Data1
+		updated.Data2 = want.Data2
+		updated.Data3 = want.Data3
+		updated.Data4 = want.Data4
+		updated.TTL = want.TTL
+		updated.Publishing = want.Publishing
+
+		changes.Update = append(changes.Update, Change{Old: record, New: &updated})
+	}
+
+	for j, record := range current {
+		if !used[j] {
+			changes.Delete = append(changes.Delete, record)
+		}
+	}
+
+	return changes
+}
+
+// ApplyChanges deletes, updates and creates the records in this order, it stops at the first error.
+//
+// Deleting first frees owners for the records created afterwards.
+func ApplyChanges(client *qip.Client, changes *Changes) error {
+	for _, record := range changes.Delete {
+		err := Delete(client, record)
+		if err != nil {
+			return fmt.Errorf("could not delete %s record %s: %w", record.RRType, record.Owner, err)
+		}
+	}
+
+	for _, change := range changes.Update {
+		err := Update(client, change.Old, change.New)
+		if err != nil {
+			return fmt.Errorf("could not update %s record %s: %w", change.Old.RRType, change.Old.Owner, err)
+		}
+	}
+
+	for _, record := range changes.Create {
+		err := Create(client, record)
+		if err != nil {
+			return fmt.Errorf("could not create %s record %s: %w", record.RRType, record.Owner, err)
+		}
+	}
+
+	return nil
+}
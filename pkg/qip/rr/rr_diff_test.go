@@ -0,0 +1,109 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rr_test
+
+import (
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/rr"
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/test"
+)
+
+func TestDiff(t *testing.T) {
+	current := []*rr.RR{
+		rr.NewAForObject("www.example.com", "192.0.2.50"),
+		rr.NewTXTForObject("host.example.com", "192.0.2.50", "first"),
+		rr.NewTXTForObject("host.example.com", "192.0.2.50", "second"),
+		rr.NewCNAMEForObject("old.example.com", "host.example.com", "192.0.2.50"),
+	}
+
+	ttl := rr.NewAForObject("WWW.example.com.", "192.0.2.50")
+	ttl.TTL = 300
+
+	desired := []*rr.RR{
+		ttl,
+		rr.NewTXTForObject("host.example.com", "192.0.2.50", "second"),
+		rr.NewTXTForObject("host.example.com", "192.0.2.50", "third"),
+		rr.NewCNAMEForObject("new.example.com", "host.example.com", "192.0.2.50"),
+	}
+
+	changes := rr.Diff(current, desired)
+
+	if assert.Len(t, changes.Update, 2) {
+		assert.Same(t, current[0], changes.Update[0].Old)
+		assert.Equal(t, 300, changes.Update[0].New.TTL)
+		assert.Equal(t, "www.example.com", changes.Update[0].New.Owner)
+
+		// The unchanged TXT record is kept, the other one updated
+		assert.Same(t, current[1], changes.Update[1].Old)
+		assert.Equal(t, desired[2].Data1, changes.Update[1].New.Data1)
+	}
+
+	assert.Equal(t, []*rr.RR{desired[3]}, changes.Create)
+	assert.Equal(t, []*rr.RR{current[3]}, changes.Delete)
+	assert.False(t, changes.Empty())
+
+	assert.True(t, rr.Diff(current, current).Empty())
+}
+
+func TestDiff_CanonicalData(t *testing.T) {
+	unquoted := rr.NewTXTForObject("host.example.com", "192.0.2.50", "text")
+	unquoted.Data1 = "text"
+
+	current := []*rr.RR{
+		unquoted,
+		rr.NewCNAMEForObject("www.example.com", "Host.Example.com.", "192.0.2.50"),
+	}
+
+	desired := []*rr.RR{
+		rr.NewTXTForObject("host.example.com", "192.0.2.50", "text"),
+		rr.NewCNAMEForObject("www.example.com", "host.example.com", "192.0.2.50"),
+	}
+
+	assert.True(t, rr.Diff(current, desired).Empty())
+}
+
+func TestApplyChanges(t *testing.T) {
+	c, cleanup := test.GetTestClient(t)
+	defer cleanup()
+
+	url := test.QIPServer + "/api/v1/" + test.QIPOrg + "/rr"
+
+	httpmock.RegisterResponder("DELETE", url, httpmock.NewStringResponder(200, `OK`))
+	httpmock.RegisterResponder("PUT", url, httpmock.NewStringResponder(200, `OK`))
+	httpmock.RegisterResponder("POST", url, httpmock.NewStringResponder(500, `failed`))
+
+	record := rr.NewAForObject("www.example.com", "192.0.2.50")
+	changes := &rr.Changes{
+		Update: []rr.Change{{Old: record, New: record}},
+		Delete: []*rr.RR{record},
+		Create: []*rr.RR{record},
+	}
+
+	err := rr.ApplyChanges(c, changes)
+	require.ErrorContains(t, err, "could not create A record www.example.com")
+
+	info := httpmock.GetCallCountInfo()
+	assert.Equal(t, 1, info["DELETE "+url])
+	assert.Equal(t, 1, info["PUT "+url])
+}
@@ -0,0 +1,216 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rr
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/dnsname"
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip"
+)
+
+var ErrInvalidInfra = errors.New("invalid infrastructure for record")
+
+// Infra identifies the infrastructure in QIP a record belongs to.
+//
+// Objects are identified by their address, zones and nodes by their FQDN.
+type Infra struct {
+	Type    string
+	Address string
+	FQDN    string
+}
+
+// ForObject returns the infrastructure of an IPv4 address object.
+func ForObject(address string) Infra {
+	return Infra{Type: InfraTypeObject, Address: address}
+}
+
+// ForV6Address returns the infrastructure of an IPv6 address object, the address is stored in its canonical form.
+func ForV6Address(address string) Infra {
+	return Infra{Type: InfraTypeV6Address, Address: canonicalIPv6(address)}
+}
+
+// ForZone returns the infrastructure of a forward zone.
+func ForZone(zone string) Infra {
+	return Infra{Type: InfraTypeZone, FQDN: dnsname.Canonical(zone)}
+}
+
+// ForV4ReverseZone returns the infrastructure of an IPv4 reverse zone (e.g. 2.0.192.in-addr.arpa).
+func ForV4ReverseZone(zone string) Infra {
+	return Infra{Type: InfraTypeV4ReverseZone, FQDN: dnsname.Canonical(zone)}
+}
+
+// ForV6ReverseZone returns the infrastructure of an IPv6 reverse zone (e.g. 8.b.d.0.1.0.0.2.ip6.arpa).
+func ForV6ReverseZone(zone string) Infra {
+	return Infra{Type: InfraTypeV6ReverseZone, FQDN: dnsname.Canonical(zone)}
+}
+
+// ForNode returns the infrastructure of a node (e.g. a DNS server).
+func ForNode(fqdn string) Infra {
+	return Infra{Type: InfraTypeNode, FQDN: dnsname.Canonical(fqdn)}
+}
+
+// byAddress checks if the infrastructure type is identified by an address.
+func (infra Infra) byAddress() bool {
+	return infra.Type == InfraTypeObject || infra.Type == InfraTypeV6Address
+}
+
+// byFQDN checks if the infrastructure type is identified by a FQDN.
+func (infra Infra) byFQDN() bool {
+	switch infra.Type {
+	case InfraTypeZone, InfraTypeV4ReverseZone, InfraTypeV6ReverseZone, InfraTypeNode:
+		return true
+	}
+
+	return false
+}
+
+// Validate checks that the identifying attribute of the infrastructure type is set.
+func (infra Infra) Validate() error {
+	switch {
+	case infra.byAddress() && infra.Address == "":
+		return fmt.Errorf("%w: %s requires an address", ErrInvalidInfra, infra.Type)
+	case infra.byFQDN() && infra.FQDN == "":
+		return fmt.Errorf("%w: %s requires a FQDN", ErrInvalidInfra, infra.Type)
+	case !infra.byAddress() && !infra.byFQDN():
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidInfra, infra.Type)
+	}
+
+	return nil
+}
+
+// Infra returns the infrastructure the record belongs to.
+//
+// Both identifying attributes are kept for unknown infrastructure types.
+func (record *RR) Infra() Infra {
+	infra := Infra{Type: record.InfraType}
+
+	if !infra.byFQDN() {
+		infra.Address = record.InfraAddr
+	}
+
+	if !infra.byAddress() {
+		infra.FQDN = record.InfraFQDN
+	}
+
+	return infra
+}
+
+// New returns a RR of any type belonging to the infrastructure, data is stored in Data1 to Data4.
+func New(infra Infra, owner, rrType string, data ...string) *RR {
+	record := &RR{
+		Owner:                   owner,
+		ClassType:               "IN",
+		RRType:                  rrType,
+		InfraType:               infra.Type,
+		Publishing:              PublishingAlways,
+		TTL:                     TTLDefault,
+		IsCreatingReverseZoneRR: false,
+		IsDefaultRR:             false,
+	}
+
+	if infra.byFQDN() {
+		record.InfraFQDN = infra.FQDN
+	} else {
+		record.InfraAddr = infra.Address
+	}
+
+	for i, field := range []*string{&record.Data1, &record.Data2, &record.Data3, &record.Data4} {
+		if i < len(data) {
+			*field = data[i]
+		}
+	}
+
+	return record
+}
+
+// NewForObject returns a RR of any type belonging to an IPv4 address object in QIP.
+func NewForObject(owner, rrType, address string, data ...string) *RR {
+	return New(ForObject(address), owner, rrType, data...)
+}
+
+// NewForZone returns a RR of any type belonging to a forward zone in QIP.
+func NewForZone(owner, rrType, zone string, data ...string) *RR {
+	return New(ForZone(zone), owner, rrType, data...)
+}
+
+// NewAAAA returns a RR for a AAAA record belonging to the infrastructure, the address is stored in its canonical form.
+func NewAAAA(infra Infra, owner, address string) *RR {
+	return New(infra, owner, RRTypeAAAA, canonicalIPv6(address))
+}
+
+// NewAAAAForV6Address returns a RR for a AAAA record belonging to an IPv6 address object in QIP.
+func NewAAAAForV6Address(owner, address string) *RR {
+	infra := ForV6Address(address)
+
+	return New(infra, owner, RRTypeAAAA, infra.Address)
+}
+
+// NewDeleteInfo returns the DeleteInfo to delete a single record.
+//
+// Only the attribute identifying the infrastructure type is sent, QIP rejects requests for objects with a
+// FQDN and for zones with an address.
+func NewDeleteInfo(record *RR) *DeleteInfo {
+	deleteInfo := &DeleteInfo{
+		Owner:        record.Owner,
+		RRType:       record.RRType,
+		InfraType:    record.InfraType,
+		SingleDelete: true,
+	}
+
+	infra := record.Infra()
+	deleteInfo.InfraAddr = infra.Address
+	deleteInfo.InfraFQDN = infra.FQDN
+
+	return deleteInfo
+}
+
+// LoadAllForInfra returns all non default records of the infrastructure.
+func LoadAllForInfra(client *qip.Client, infra Infra, opts *LoadOptions) ([]*RR, error) {
+	err := infra.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	query := &Query{InfraType: infra.Type, Address: infra.Address, FQDN: infra.FQDN}
+
+	return Search(client, opts.query(query))
+}
+
+// LoadAllForV6Address returns all non default records of the IPv6 address object, tombstoned records are left out.
+func LoadAllForV6Address(client *qip.Client, address string) ([]*RR, error) {
+	return LoadAllForInfra(client, ForV6Address(address), nil)
+}
+
+// LoadAllForZone returns all non default records of the forward zone, tombstoned records are left out.
+func LoadAllForZone(client *qip.Client, zone string) ([]*RR, error) {
+	return LoadAllForInfra(client, ForZone(zone), nil)
+}
+
+// canonicalIPv6 returns the compressed form of an IPv6 address, invalid values are kept.
+func canonicalIPv6(address string) string {
+	addr, err := netip.ParseAddr(address)
+	if err != nil {
+		return address
+	}
+
+	return addr.String()
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rr_test
+
+import (
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/rr"
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/test"
+)
+
+func TestNew(t *testing.T) {
+	record := rr.New(rr.ForZone("Example.com."), "example.com", rr.RRTypeMX, "10", "mail.example.com")
+
+	assert.Equal(t, rr.InfraTypeZone, record.InfraType)
+	assert.Equal(t, "example.com", record.InfraFQDN)
+	assert.Empty(t, record.InfraAddr)
+	assert.Equal(t, rr.ForZone("example.com"), record.Infra())
+
+	record = rr.NewForObject("host.example.com", rr.RRTypeA, "192.0.2.50", "192.0.2.50")
+	assert.Equal(t, "192.0.2.50", record.InfraAddr)
+	assert.Empty(t, record.InfraFQDN)
+	assert.Equal(t, rr.ForObject("192.0.2.50"), record.Infra())
+}
+
+func TestNewAAAAForV6Address(t *testing.T) {
+	record := rr.NewAAAAForV6Address("host.example.com", "2001:0DB8:0000:0000:0000:0000:0000:0050")
+
+	assert.Equal(t, rr.RRTypeAAAA, record.RRType)
+	assert.Equal(t, rr.InfraTypeV6Address, record.InfraType)
+	assert.Equal(t, "2001:db8::50", record.InfraAddr)
+	assert.Equal(t, "2001:db8::50", record.Data1)
+}
+
+func TestNewAAAA(t *testing.T) {
+	record := rr.NewAAAA(rr.ForZone("example.com"), "web.example.com", "2001:0DB8::0010")
+
+	assert.Equal(t, rr.RRTypeAAAA, record.RRType)
+	assert.Equal(t, rr.InfraTypeZone, record.InfraType)
+	assert.Equal(t, "example.com", record.InfraFQDN)
+	assert.Equal(t, "2001:db8::10", record.Data1)
+}
+
+func TestInfra_Validate(t *testing.T) {
+	require.NoError(t, rr.ForObject("192.0.2.50").Validate())
+	require.NoError(t, rr.ForV4ReverseZone("2.0.192.in-addr.arpa").Validate())
+
+	require.ErrorIs(t, rr.ForObject("").Validate(), rr.ErrInvalidInfra)
+	require.ErrorIs(t, rr.ForNode("").Validate(), rr.ErrInvalidInfra)
+	require.ErrorIs(t, rr.Infra{Type: rr.InfraTypeAll}.Validate(), rr.ErrInvalidInfra)
+}
+
+func TestNewDeleteInfo(t *testing.T) {
+	record := rr.NewAForObject("host.example.com", "192.0.2.50")
+	record.InfraFQDN = "ignored.example.com"
+
+	deleteInfo := rr.NewDeleteInfo(record)
+	assert.Equal(t, "192.0.2.50", deleteInfo.InfraAddr)
+	assert.Empty(t, deleteInfo.InfraFQDN)
+	assert.True(t, deleteInfo.SingleDelete)
+
+	record = rr.New(rr.ForV6ReverseZone("8.b.d.0.1.0.0.2.ip6.arpa"), "0.5.0.0.8.b.d.0.1.0.0.2.ip6.arpa",
+		rr.RRTypePTR, "host.example.com")
+	record.InfraAddr = "2001:db8::50"
+
+	deleteInfo = rr.NewDeleteInfo(record)
+	assert.Empty(t, deleteInfo.InfraAddr)
+	assert.Equal(t, "8.b.d.0.1.0.0.2.ip6.arpa", deleteInfo.InfraFQDN)
+	assert.Equal(t, rr.InfraTypeV6ReverseZone, deleteInfo.InfraType)
+}
+
+func TestLoadAllForInfra(t *testing.T) {
+	c, cleanup := test.GetTestClient(t)
+	defer cleanup()
+
+	url := test.QIPServer + "/api/v1/" + test.QIPOrg + "/rr.json"
+
+	httpmock.RegisterResponderWithQuery("GET", url, "type=V6ADDRESS&address=2001:db8::50&getDefaultRRs=false",
+		httpmock.NewStringResponder(200, `{"list":[{"owner":"host.example.com","rrType":"AAAA","data1":"2001:db8::50",
+			"infraType":"V6ADDRESS","infraAddr":"2001:db8::50"}]}`))
+	httpmock.RegisterResponderWithQuery("GET", url, "type=ZONE&fqdn=example.com&getDefaultRRs=false",
+		httpmock.NewStringResponder(200, `{"list":[{"owner":"example.com","rrType":"MX","data1":"10",
+			"data2":"mail.example.com","infraType":"ZONE","infraFQDN":"example.com"}]}`))
+
+	records, err := rr.LoadAllForV6Address(c, "2001:db8:0::50")
+	require.NoError(t, err)
+
+	if assert.Len(t, records, 1) {
+		assert.Equal(t, rr.RRTypeAAAA, records[0].RRType)
+	}
+
+	records, err = rr.LoadAllForInfra(c, rr.ForZone("example.com"), nil)
+	require.NoError(t, err)
+
+	if assert.Len(t, records, 1) {
+		assert.Equal(t, rr.ForZone("example.com"), records[0].Infra())
+	}
+
+	_, err = rr.LoadAllForInfra(c, rr.Infra{Type: rr.InfraTypeZone}, nil)
+	require.ErrorIs(t, err, rr.ErrInvalidInfra)
+}
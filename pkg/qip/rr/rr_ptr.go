@@ -0,0 +1,147 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rr
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/dnsname"
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip"
+)
+
+// Suffixes of the reverse DNS trees for IPv4 and IPv6.
+const (
+	ReverseSuffixV4 = "in-addr.arpa"
+	ReverseSuffixV6 = "ip6.arpa"
+)
+
+var ErrInvalidAddress = errors.New("invalid IP address")
+
+// ReverseName returns the owner of the PTR record for an address (e.g. 50.2.0.192.in-addr.arpa).
+func ReverseName(address string) (string, error) {
+	addr, err := netip.ParseAddr(address)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidAddress, address)
+	}
+
+	var labels []string
+
+	if addr.Is4() {
+		for _, octet := range addr.As4() {
+			labels = append([]string{strconv.Itoa(int(octet))}, labels...)
+		}
+
+		return strings.Join(labels, ".") + "." + ReverseSuffixV4, nil
+	}
+
+	for _, octet := range addr.As16() {
+		labels = append([]string{
+			strconv.FormatUint(uint64(octet&0xf), 16), //nolint:gomnd
+			strconv.FormatUint(uint64(octet>>4), 16),  //nolint:gomnd
+		}, labels...)
+	}
+
+	return strings.Join(labels, ".") + "." + ReverseSuffixV6, nil
+}
+
+// ForZoneName returns the infrastructure of a zone, reverse zones are detected by their suffix.
+func ForZoneName(zone string) Infra {
+	zone = dnsname.Canonical(zone)
+
+	switch {
+	case zone == ReverseSuffixV4 || strings.HasSuffix(zone, "."+ReverseSuffixV4):
+		return ForV4ReverseZone(zone)
+	case zone == ReverseSuffixV6 || strings.HasSuffix(zone, "."+ReverseSuffixV6):
+		return ForV6ReverseZone(zone)
+	}
+
+	return ForZone(zone)
+}
+
+// NewPTRForReverseZone returns a RR for the PTR record of an address in a reverse zone.
+func NewPTRForReverseZone(zone, address, target string) (*RR, error) {
+	owner, err := ReverseName(address)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPTR(ForZoneName(zone), owner, target), nil
+}
+
+// IsReverseZone checks if the record belongs to an IPv4 or IPv6 reverse zone.
+func (record *RR) IsReverseZone() bool {
+	return record.InfraType == InfraTypeV4ReverseZone || record.InfraType == InfraTypeV6ReverseZone
+}
+
+// LoadReversePTR returns the PTR records in reverse zones pointing back to the owner of an A or AAAA record.
+//
+// QIP creates these records for a record with IsCreatingReverseZoneRR. No records are returned, when QIP
+// has no records for the reverse name.
+func LoadReversePTR(client *qip.Client, record *RR) ([]*RR, error) {
+	owner, err := ReverseName(record.Data1)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := Search(client, &Query{Owner: owner, RRType: RRTypePTR})
+	if err != nil {
+		var notFoundErr *qip.HTTPNotFoundError
+		if errors.As(err, &notFoundErr) {
+			// No records for the reverse name
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var matching []*RR
+
+	for _, ptr := range records {
+		if ptr.IsReverseZone() && dnsname.Equal(ptr.Data1, record.Owner) {
+			matching = append(matching, ptr)
+		}
+	}
+
+	return matching, nil
+}
+
+// DeleteReversePTR deletes the PTR record QIP created in a reverse zone for an A or AAAA record with
+// IsCreatingReverseZoneRR.
+//
+// Only a single PTR pointing back to the owner is deleted, other PTR records of the address (e.g. created
+// before or by hand) are kept.
+func DeleteReversePTR(client *qip.Client, record *RR) error {
+	records, err := LoadReversePTR(client, record)
+	if err != nil || len(records) == 0 {
+		return err
+	}
+
+	ptr := records[len(records)-1]
+
+	err = Delete(client, ptr)
+	if err != nil {
+		return fmt.Errorf("could not delete PTR %s: %w", ptr.Owner, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rr_test
+
+import (
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/rr"
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/test"
+)
+
+func TestReverseName(t *testing.T) {
+	name, err := rr.ReverseName("192.0.2.50")
+	require.NoError(t, err)
+	assert.Equal(t, "50.2.0.192.in-addr.arpa", name)
+
+	name, err = rr.ReverseName("2001:db8::5f")
+	require.NoError(t, err)
+	assert.Equal(t, "f.5.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa", name)
+
+	_, err = rr.ReverseName("host.example.com")
+	require.ErrorIs(t, err, rr.ErrInvalidAddress)
+}
+
+func TestForZoneName(t *testing.T) {
+	assert.Equal(t, rr.ForV4ReverseZone("2.0.192.in-addr.arpa"), rr.ForZoneName("2.0.192.IN-ADDR.ARPA."))
+	assert.Equal(t, rr.ForV6ReverseZone("8.b.d.0.1.0.0.2.ip6.arpa"), rr.ForZoneName("8.b.d.0.1.0.0.2.ip6.arpa"))
+	assert.Equal(t, rr.ForZone("in-addr.arpa.example.com"), rr.ForZoneName("in-addr.arpa.example.com"))
+
+	record, err := rr.NewPTRForReverseZone("2.0.192.in-addr.arpa", "192.0.2.50", "host.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "50.2.0.192.in-addr.arpa", record.Owner)
+	assert.Equal(t, rr.InfraTypeV4ReverseZone, record.InfraType)
+	assert.True(t, record.IsReverseZone())
+}
+
+func TestDeleteReversePTR(t *testing.T) {
+	c, cleanup := test.GetTestClient(t)
+	defer cleanup()
+
+	url := test.QIPServer + "/api/v1/" + test.QIPOrg + "/rr"
+
+	httpmock.RegisterResponderWithQuery("GET", url+".json", "owner=50.2.0.192.in-addr.arpa&getDefaultRRs=false",
+		httpmock.NewStringResponder(200, `{"list":[
+			{"owner":"50.2.0.192.in-addr.arpa","rrType":"PTR","data1":"extra.example.com",
+				"infraType":"V4REVERSEZONE","infraFQDN":"2.0.192.in-addr.arpa"},
+			{"owner":"50.2.0.192.in-addr.arpa","rrType":"PTR","data1":"other.example.com",
+				"infraType":"V4REVERSEZONE","infraFQDN":"2.0.192.in-addr.arpa"},
+			{"owner":"50.2.0.192.in-addr.arpa","rrType":"PTR","data1":"extra.example.com",
+				"infraType":"OBJECT","infraAddr":"192.0.2.50"},
+			{"owner":"50.2.0.192.in-addr.arpa","rrType":"PTR","data1":"extra.example.com",
+				"infraType":"V4REVERSEZONE","infraFQDN":"2.0.192.in-addr.arpa"}]}`))
+	httpmock.RegisterResponder("DELETE", url, httpmock.NewStringResponder(200, `OK`))
+
+	record := rr.NewAForObject("Extra.example.com.", "192.0.2.50")
+
+	records, err := rr.LoadReversePTR(c, record)
+	require.NoError(t, err)
+
+	if assert.Len(t, records, 2) {
+		assert.Equal(t, "2.0.192.in-addr.arpa", records[0].InfraFQDN)
+	}
+
+	// Only one of them was created by QIP for the record
+	err = rr.DeleteReversePTR(c, record)
+	require.NoError(t, err)
+	assert.Equal(t, 1, httpmock.GetCallCountInfo()["DELETE "+url])
+
+	// QIP has no records for the reverse name
+	httpmock.RegisterResponderWithQuery("GET", url+".json", "owner=50.2.0.192.in-addr.arpa&getDefaultRRs=false",
+		httpmock.NewStringResponder(404, ""))
+
+	records, err = rr.LoadReversePTR(c, record)
+	require.NoError(t, err)
+	assert.Empty(t, records)
+
+	err = rr.DeleteReversePTR(c, record)
+	require.NoError(t, err)
+	assert.Equal(t, 1, httpmock.GetCallCountInfo()["DELETE "+url])
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rr
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/dnsname"
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip"
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/rest"
+)
+
+// Query filters the records returned by Search, empty fields are not filtered.
+type Query struct {
+	// InfraType limits the records to a type of infrastructure, see Infra.
+	InfraType string
+	// Address of the infrastructure, for objects and IPv6 address objects.
+	Address string
+	// FQDN of the infrastructure, for zones and nodes.
+	FQDN string
+	// Owner only returns records of exactly this owner.
+	Owner string
+	// OwnerSuffix only returns records with an owner within this domain, including the domain itself.
+	OwnerSuffix string
+	// RRType only returns records of this type, e.g. RRTypeA.
+	RRType string
+	// IncludeTombstoned returns deleted records, that are still kept by QIP as a tombstone.
+	IncludeTombstoned bool
+	// IncludeDefault returns the default records QIP generates for objects, e.g. the A record of the object name.
+	IncludeDefault bool
+}
+
+// Search returns all records of the organization matching the query, default records only when included.
+//
+// Infrastructure and owner are filtered by QIP, the other fields after loading the records.
+func Search(client *qip.Client, query *Query) ([]*RR, error) {
+	if query == nil {
+		query = &Query{}
+	}
+
+	values := url.Values{}
+	setQueryValue(values, "type", query.InfraType)
+	setQueryValue(values, "address", query.Address)
+	setQueryValue(values, "fqdn", query.FQDN)
+	setQueryValue(values, "owner", query.Owner)
+	values.Set("getDefaultRRs", strconv.FormatBool(query.IncludeDefault))
+
+	request, err := rest.NewRequest("GET", client.APITenantURL("rr.json")+"?"+values.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not build get request: %w", err)
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("could not load RR: %w", err)
+	}
+
+	var results loadResult
+
+	err = rest.UnmarshalResponse(response, &results)
+	if err != nil {
+		return nil, fmt.Errorf("could not unmarshal JSON result: %w", err)
+	}
+
+	records := make([]*RR, 0, len(results.List))
+
+	for _, record := range results.List {
+		if query.matches(record) {
+			records = append(records, record)
+		}
+	}
+
+	return records, nil
+}
+
+// matches checks the fields of the query, that are not filtered by QIP.
+func (query *Query) matches(record *RR) bool {
+	if record.IsTombstoned() && !query.IncludeTombstoned {
+		return false
+	}
+
+	if query.RRType != "" && !strings.EqualFold(record.RRType, query.RRType) {
+		return false
+	}
+
+	if query.OwnerSuffix != "" {
+		owner := dnsname.Canonical(record.Owner)
+		suffix := dnsname.Canonical(query.OwnerSuffix)
+
+		if owner != suffix && !strings.HasSuffix(owner, "."+suffix) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func setQueryValue(values url.Values, key, value string) {
+	if value != "" {
+		values.Set(key, value)
+	}
+}
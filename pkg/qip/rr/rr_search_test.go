@@ -0,0 +1,70 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rr_test
+
+import (
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/rr"
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/test"
+)
+
+func TestSearch(t *testing.T) {
+	c, cleanup := test.GetTestClient(t)
+	defer cleanup()
+
+	httpmock.RegisterResponderWithQuery("GET", test.QIPServer+"/api/v1/"+test.QIPOrg+"/rr.json",
+		"type=ZONE&fqdn=example.com&getDefaultRRs=false",
+		httpmock.NewStringResponder(200, `{"list":[
+			{"owner":"example.com","rrType":"MX","data1":"10","data2":"mail.example.com",
+				"infraType":"ZONE","infraFQDN":"example.com"},
+			{"owner":"_ldap._tcp.example.com","rrType":"SRV","data1":"0","data2":"5","data3":"389",
+				"data4":"dc1.example.com","infraType":"ZONE","infraFQDN":"example.com"},
+			{"owner":"_ldap._tcp.ad.example.com","rrType":"SRV","data1":"0","data2":"5","data3":"389",
+				"data4":"dc2.example.com","infraType":"ZONE","infraFQDN":"example.com"},
+			{"owner":"_ldap._tcp.bad.example.com","rrType":"SRV","data1":"0","data2":"5","data3":"389",
+				"data4":"dc3.example.com","infraType":"ZONE","infraFQDN":"example.com","tombstoned":1}]}`))
+
+	query := &rr.Query{InfraType: rr.InfraTypeZone, FQDN: "example.com"}
+
+	records, err := rr.Search(c, query)
+	require.NoError(t, err)
+	assert.Len(t, records, 3)
+
+	query.RRType = "srv"
+
+	records, err = rr.Search(c, query)
+	require.NoError(t, err)
+	assert.Len(t, records, 2)
+
+	// Label aligned, bad.example.com is not within ad.example.com
+	query.OwnerSuffix = "AD.example.com."
+	query.IncludeTombstoned = true
+
+	records, err = rr.Search(c, query)
+	require.NoError(t, err)
+
+	if assert.Len(t, records, 1) {
+		assert.Equal(t, "dc2.example.com", records[0].Data4)
+	}
+}
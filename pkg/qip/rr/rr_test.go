@@ -29,6 +29,14 @@ import (
 	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/test"
 )
 
+func TestRR_Equal(t *testing.T) {
+	record := rr.NewAForObject("Entry.Example.com.", "192.0.2.50")
+
+	assert.True(t, record.Equal(rr.NewAForObject("entry.example.com", "192.0.2.50")))
+	assert.False(t, record.Equal(rr.NewAForObject("other.example.com", "192.0.2.50")))
+	assert.False(t, record.Equal(rr.NewAForObject("entry.example.com", "192.0.2.51")))
+}
+
 func TestLoadAllForObject(t *testing.T) {
 	c, cleanup := test.GetTestClient(t)
 	defer cleanup()
@@ -61,6 +69,44 @@ func TestLoadAllForObject(t *testing.T) {
 	}
 }
 
+func TestLoadAllForObject_Tombstoned(t *testing.T) {
+	c, cleanup := test.GetTestClient(t)
+	defer cleanup()
+
+	httpmock.RegisterResponder("GET", test.QIPServer+"/api/v1/"+test.QIPOrg+"/rr.json",
+		httpmock.NewStringResponder(200, `{"list":[
+			{"owner":"old.example.com","rrType":"A","data1":"192.0.2.50","infraAddr":"192.0.2.50","tombstoned":1},
+			{"owner":"new.example.com","rrType":"A","data1":"192.0.2.50","infraAddr":"192.0.2.50","tombstoned":0}]}`))
+
+	records, err := rr.LoadAllForObject(c, "192.0.2.50")
+	require.NoError(t, err)
+
+	if assert.Len(t, records, 1) {
+		assert.Equal(t, "new.example.com", records[0].Owner)
+	}
+
+	records, err = rr.LoadAllForObjectWithOptions(c, "192.0.2.50", &rr.LoadOptions{IncludeTombstoned: true})
+	require.NoError(t, err)
+	assert.Len(t, records, 2)
+}
+
+func TestLoadAllForOwner(t *testing.T) {
+	c, cleanup := test.GetTestClient(t)
+	defer cleanup()
+
+	httpmock.RegisterResponderWithQuery("GET", test.QIPServer+"/api/v1/"+test.QIPOrg+"/rr.json",
+		"owner=www.int.example.com&getDefaultRRs=false",
+		httpmock.NewStringResponder(200, `{"list":[{"owner":"www.int.example.com","rrType":"A","data1":"192.0.2.50",
+			"infraType":"OBJECT","infraAddr":"192.0.2.50"}]}`))
+
+	records, err := rr.LoadAllForOwner(c, "www.int.example.com")
+	require.NoError(t, err)
+
+	if assert.Len(t, records, 1) {
+		assert.Equal(t, "192.0.2.50", records[0].InfraAddr)
+	}
+}
+
 func TestCreate(t *testing.T) {
 	c, cleanup := test.GetTestClient(t)
 	defer cleanup()
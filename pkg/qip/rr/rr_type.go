@@ -17,4 +17,5 @@ type RR struct {
 	InfraAddr               string `json:"infraAddr,omitempty"`
 	IsCreatingReverseZoneRR bool   `json:"isCreatingReverseZoneRR,omitempty"`
 	IsDefaultRR             bool   `json:"isDefaultRR,omitempty"`
+	Tombstoned              int    `json:"tombstoned,omitempty"`
 }
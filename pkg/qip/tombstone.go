@@ -0,0 +1,46 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package qip
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrTombstoned is returned for deleted objects, that are still kept by QIP as a tombstone.
+//
+// It is always combined with HTTPNotFoundError by NewTombstonedError, so callers treating a 404 as gone
+// handle tombstones the same way.
+var ErrTombstoned = errors.New("object is tombstoned")
+
+// NewTombstonedError returns an error matching ErrTombstoned and HTTPNotFoundError.
+func NewTombstonedError(name string) error {
+	return fmt.Errorf("%w: %s: %w", ErrTombstoned, name, &HTTPNotFoundError{})
+}
+
+// IsTombstoned checks the tombstoned flag as returned by QIP for objects and records.
+func IsTombstoned(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "true", "1", "yes":
+		return true
+	}
+
+	return false
+}
@@ -32,7 +32,24 @@ var (
 	ErrObjectNameRequired = errors.New("ObjectName is required")
 )
 
+// LoadOptions change which objects are returned by LoadWithOptions.
+type LoadOptions struct {
+	// IncludeTombstoned returns deleted objects, that are still kept by QIP as a tombstone.
+	IncludeTombstoned bool
+}
+
+// Load an object, tombstoned objects are reported as not found.
 func Load(client *qip.Client, address string) (*V4Address, error) {
+	return LoadWithOptions(client, address, nil)
+}
+
+// LoadWithOptions loads an object, tombstoned objects return an error matching qip.ErrTombstoned
+// and qip.HTTPNotFoundError unless they are included by the options.
+func LoadWithOptions(client *qip.Client, address string, opts *LoadOptions) (*V4Address, error) {
+	if opts == nil {
+		opts = &LoadOptions{}
+	}
+
 	request, err := rest.NewRequest("GET", client.APITenantURL("v4address", address+".json"), nil)
 	if err != nil {
 		return nil, fmt.Errorf("could not build get request: %w", err)
@@ -50,9 +67,18 @@ func Load(client *qip.Client, address string) (*V4Address, error) {
 		return nil, fmt.Errorf("could not unmarshal JSON result: %w", err)
 	}
 
+	if !opts.IncludeTombstoned && o.IsTombstoned() {
+		return nil, fmt.Errorf("could not load V4Address: %w", qip.NewTombstonedError(address))
+	}
+
 	return &o, nil
 }
 
+// IsTombstoned checks if the object is only kept as a tombstone of a deleted object.
+func (addr *V4Address) IsTombstoned() bool {
+	return qip.IsTombstoned(addr.Tombstoned)
+}
+
 // Create a V4Address from input values.
 //
 // Required fields:
@@ -85,6 +111,8 @@ func Create(client *qip.Client, addr *V4Address) error {
 // Recommended uses:
 //   - LoadV4Address -> Update
 //   - SelectV4Address -> Update
+//
+// Use Patch to change only some fields of an existing object.
 func Update(client *qip.Client, addr *V4Address) error {
 	if addr.ObjectAddr == "" || addr.SubnetAddr == "" {
 		return ErrBothAddrRequired
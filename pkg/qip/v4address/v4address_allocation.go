@@ -0,0 +1,455 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v4address
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip"
+)
+
+// Strategies on how to pick a free address from the candidate ranges.
+const (
+	// AllocationDefault lets QIP pick any free address within the candidate ranges.
+	AllocationDefault = "default"
+	// AllocationLowest picks the lowest free address by probing single addresses from the start.
+	AllocationLowest = "lowest"
+	// AllocationHighest picks the highest free address by probing single addresses from the end.
+	AllocationHighest = "highest"
+	// AllocationRandom picks a random free address by probing single addresses.
+	AllocationRandom = "random"
+	// AllocationOffset picks the lowest free address at or after the first host following Allocation.Offset hosts,
+	// by probing single addresses like AllocationLowest.
+	AllocationOffset = "offset"
+)
+
+// AllocationStrategies lists all known strategies.
+var AllocationStrategies = []string{ //nolint:gochecknoglobals
+	AllocationDefault,
+	AllocationLowest,
+	AllocationHighest,
+	AllocationRandom,
+	AllocationOffset,
+}
+
+// MaxAllocationProbes limits how many single addresses are tried by probing strategies.
+const MaxAllocationProbes = 256
+
+var (
+	ErrUnknownAllocationStrategy = errors.New("unknown allocation strategy")
+	ErrNoCandidateAddress        = errors.New("no candidate address left to select from")
+	ErrProbeLimitReached         = errors.New("no free address found within the probe limit")
+	ErrInvalidAddress            = errors.New("not a valid IPv4 address")
+	ErrInvalidAddressRange       = errors.New("not a valid IPv4 address range")
+)
+
+// Allocation describes which addresses of a subnet may be selected and how to pick one.
+type Allocation struct {
+	Strategy string
+	// Offset is the number of host addresses at the start of the subnet that are never used.
+	// It limits the candidates of every strategy, not only of AllocationOffset.
+	Offset int
+	// RangeStart and RangeEnd optionally limit the selection to a part of the subnet.
+	RangeStart string
+	RangeEnd   string
+	// Exclude lists addresses, ranges (first-last) or networks in CIDR notation that must not be selected.
+	Exclude []string
+}
+
+// addrRange is an inclusive range of IPv4 addresses in numeric form.
+type addrRange struct {
+	first uint32
+	last  uint32
+}
+
+func (r addrRange) size() uint32 {
+	return r.last - r.first + 1
+}
+
+func (r addrRange) selected() *SelectedAddrRange {
+	return &SelectedAddrRange{
+		StartAddress: uint32ToIP(r.first),
+		EndAddress:   uint32ToIP(r.last),
+	}
+}
+
+// CandidateRanges computes the ranges of host addresses of a subnet allowed by the allocation.
+//
+// The network and broadcast address are never part of the result, ranges are sorted ascending.
+func CandidateRanges(subnet, mask string, alloc *Allocation) ([]*SelectedAddrRange, error) {
+	ranges, err := candidateRanges(subnet, mask, alloc)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*SelectedAddrRange, 0, len(ranges))
+	for _, r := range ranges {
+		result = append(result, r.selected())
+	}
+
+	return result, nil
+}
+
+// SelectWithAllocation reserves a free address in the subnet according to the allocation and returns it.
+//
+// The subnet mask is required to compute the host addresses, see v4subnet.Load.
+func SelectWithAllocation(client *qip.Client, subnet, mask string, alloc *Allocation) (string, error) {
+	if alloc == nil {
+		alloc = &Allocation{}
+	}
+
+	if alloc.Strategy != "" && !slices.Contains(AllocationStrategies, alloc.Strategy) {
+		return "", fmt.Errorf("%w: %s", ErrUnknownAllocationStrategy, alloc.Strategy)
+	}
+
+	ranges, err := candidateRanges(subnet, mask, alloc)
+	if err != nil {
+		return "", err
+	}
+
+	if len(ranges) == 0 {
+		return "", ErrNoCandidateAddress
+	}
+
+	switch alloc.Strategy {
+	case "", AllocationDefault:
+		// QIP selects the next free address, trying the ranges in ascending order
+		selected := make([]*SelectedAddrRange, 0, len(ranges))
+		for _, r := range ranges {
+			selected = append(selected, r.selected())
+		}
+
+		return CreateSelectedInRanges(client, subnet, selected)
+	case AllocationLowest, AllocationOffset:
+		return probeSelect(client, subnet, ranges, lowestCandidates(ranges, MaxAllocationProbes))
+	case AllocationHighest:
+		return probeSelect(client, subnet, ranges, highestCandidates(ranges, MaxAllocationProbes))
+	default: // AllocationRandom
+		return probeSelect(client, subnet, ranges, randomCandidates(ranges, MaxAllocationProbes))
+	}
+}
+
+// probeSelect tries to select each candidate address on its own, until one is free.
+//
+// Only a refused selection of an address in use moves on to the next candidate, any other error is returned.
+func probeSelect(client *qip.Client, subnet string, ranges []addrRange, candidates []uint32) (string, error) {
+	var lastErr error
+
+	for _, candidate := range candidates {
+		addr := uint32ToIP(candidate)
+
+		selected, err := CreateSelectedInRanges(client, subnet, []*SelectedAddrRange{{addr, addr}})
+		if err == nil {
+			return selected, nil
+		}
+
+		if !isNotFreeError(err) {
+			return "", err
+		}
+
+		lastErr = err
+	}
+
+	if lastErr == nil {
+		return "", ErrNoCandidateAddress
+	}
+
+	var total uint64
+	for _, r := range ranges {
+		total += uint64(r.size())
+	}
+
+	if total > uint64(len(candidates)) {
+		return "", fmt.Errorf("%w: tried %d of %d candidate addresses, limit the candidates with a range "+
+			"or use the default strategy: %w", ErrProbeLimitReached, len(candidates), total, lastErr)
+	}
+
+	return "", fmt.Errorf("%w: %w", ErrNoCandidateAddress, lastErr)
+}
+
+// addressInUseMessages are parts of the messages QIP uses to refuse the selection of an address in use.
+var addressInUseMessages = []string{"no free address", "in use"} //nolint:gochecknoglobals
+
+// isNotFreeError checks if QIP refused a selection, because the address is in use.
+//
+// This is either an empty selection, a conflict or a client error reporting the address in use.
+func isNotFreeError(err error) bool {
+	if errors.Is(err, ErrNoSelection) {
+		return true
+	}
+
+	var clientErr *qip.HTTPClientError
+	if !errors.As(err, &clientErr) {
+		return false
+	}
+
+	if clientErr.Response != nil && clientErr.Response.StatusCode == http.StatusConflict {
+		return true
+	}
+
+	message := strings.ToLower(clientErr.Message)
+
+	for _, inUse := range addressInUseMessages {
+		if strings.Contains(message, inUse) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func lowestCandidates(ranges []addrRange, limit int) []uint32 {
+	candidates := make([]uint32, 0, limit)
+
+	for _, r := range ranges {
+		for addr := r.first; len(candidates) < limit; addr++ {
+			candidates = append(candidates, addr)
+
+			if addr == r.last {
+				break
+			}
+		}
+	}
+
+	return candidates
+}
+
+func highestCandidates(ranges []addrRange, limit int) []uint32 {
+	candidates := make([]uint32, 0, limit)
+
+	for i := len(ranges) - 1; i >= 0; i-- {
+		for addr := ranges[i].last; len(candidates) < limit; addr-- {
+			candidates = append(candidates, addr)
+
+			if addr == ranges[i].first {
+				break
+			}
+		}
+	}
+
+	return candidates
+}
+
+func randomCandidates(ranges []addrRange, limit int) []uint32 {
+	var total uint64
+	for _, r := range ranges {
+		total += uint64(r.size())
+	}
+
+	if total < uint64(limit) {
+		limit = int(total)
+	}
+
+	var (
+		candidates = make([]uint32, 0, limit)
+		seen       = make(map[uint64]bool, limit)
+	)
+
+	for len(candidates) < limit {
+		index := rand.Uint64() % total //nolint:gosec
+
+		if seen[index] {
+			continue
+		}
+
+		seen[index] = true
+
+		for _, r := range ranges {
+			if index < uint64(r.size()) {
+				candidates = append(candidates, r.first+uint32(index))
+
+				break
+			}
+
+			index -= uint64(r.size())
+		}
+	}
+
+	return candidates
+}
+
+func candidateRanges(subnet, mask string, alloc *Allocation) ([]addrRange, error) {
+	if alloc == nil {
+		alloc = &Allocation{}
+	}
+
+	hosts, err := hostRange(subnet, mask)
+	if err != nil {
+		return nil, err
+	}
+
+	if alloc.Offset > 0 {
+		if uint64(alloc.Offset) >= uint64(hosts.size()) {
+			return nil, nil
+		}
+
+		hosts.first += uint32(alloc.Offset)
+	}
+
+	if alloc.RangeStart != "" {
+		start, err := ipToUint32(alloc.RangeStart)
+		if err != nil {
+			return nil, err
+		}
+
+		hosts.first = max(hosts.first, start)
+	}
+
+	if alloc.RangeEnd != "" {
+		end, err := ipToUint32(alloc.RangeEnd)
+		if err != nil {
+			return nil, err
+		}
+
+		hosts.last = min(hosts.last, end)
+	}
+
+	if hosts.first > hosts.last {
+		return nil, nil
+	}
+
+	ranges := []addrRange{hosts}
+
+	for _, exclude := range alloc.Exclude {
+		excluded, err := parseAddressRange(exclude)
+		if err != nil {
+			return nil, err
+		}
+
+		ranges = subtractRange(ranges, excluded)
+	}
+
+	return ranges, nil
+}
+
+// hostRange returns the usable host addresses of a subnet, without network and broadcast address.
+func hostRange(subnet, mask string) (addrRange, error) {
+	network, err := ipToUint32(subnet)
+	if err != nil {
+		return addrRange{}, err
+	}
+
+	maskValue, err := ipToUint32(mask)
+	if err != nil {
+		return addrRange{}, err
+	}
+
+	network &= maskValue
+	broadcast := network | ^maskValue
+
+	if broadcast-network < 2 { //nolint:gomnd
+		// /31 and /32 have no network and broadcast address
+		return addrRange{network, broadcast}, nil
+	}
+
+	return addrRange{network + 1, broadcast - 1}, nil
+}
+
+// ValidateAddressRange checks if the value is a single address, a range "first-last" or a network in CIDR notation.
+func ValidateAddressRange(value string) error {
+	_, err := parseAddressRange(value)
+
+	return err
+}
+
+// parseAddressRange parses a single address, a range "first-last" or a network in CIDR notation.
+func parseAddressRange(value string) (addrRange, error) {
+	value = strings.TrimSpace(value)
+
+	if _, network, err := net.ParseCIDR(value); err == nil {
+		if network.IP.To4() == nil {
+			return addrRange{}, fmt.Errorf("%w: %s", ErrInvalidAddressRange, value)
+		}
+
+		first := binary.BigEndian.Uint32(network.IP.To4())
+		last := first | ^binary.BigEndian.Uint32(net.IP(network.Mask).To4())
+
+		return addrRange{first, last}, nil
+	}
+
+	first, last, isRange := strings.Cut(value, "-")
+	if !isRange {
+		last = first
+	}
+
+	firstValue, err := ipToUint32(strings.TrimSpace(first))
+	if err != nil {
+		return addrRange{}, err
+	}
+
+	lastValue, err := ipToUint32(strings.TrimSpace(last))
+	if err != nil {
+		return addrRange{}, err
+	}
+
+	if firstValue > lastValue {
+		return addrRange{}, fmt.Errorf("%w: %s", ErrInvalidAddressRange, value)
+	}
+
+	return addrRange{firstValue, lastValue}, nil
+}
+
+// subtractRange removes an excluded range from a sorted list of ranges.
+func subtractRange(ranges []addrRange, excluded addrRange) []addrRange {
+	result := make([]addrRange, 0, len(ranges)+1)
+
+	for _, r := range ranges {
+		if excluded.last < r.first || excluded.first > r.last {
+			result = append(result, r)
+
+			continue
+		}
+
+		if excluded.first > r.first {
+			result = append(result, addrRange{r.first, excluded.first - 1})
+		}
+
+		if excluded.last < r.last {
+			result = append(result, addrRange{excluded.last + 1, r.last})
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].first < result[j].first })
+
+	return result
+}
+
+func ipToUint32(address string) (uint32, error) {
+	ip := net.ParseIP(address).To4()
+	if ip == nil {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidAddress, address)
+	}
+
+	return binary.BigEndian.Uint32(ip), nil
+}
+
+func uint32ToIP(value uint32) string {
+	ip := make(net.IP, net.IPv4len)
+	binary.BigEndian.PutUint32(ip, value)
+
+	return ip.String()
+}
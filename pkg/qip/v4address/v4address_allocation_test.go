@@ -0,0 +1,198 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v4address_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/test"
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/v4address"
+)
+
+func TestCandidateRanges(t *testing.T) {
+	ranges, err := v4address.CandidateRanges("192.0.2.0", "255.255.255.0", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []*v4address.SelectedAddrRange{{"192.0.2.1", "192.0.2.254"}}, ranges)
+
+	ranges, err = v4address.CandidateRanges("192.0.2.0", "255.255.255.0", &v4address.Allocation{
+		Offset:   10,
+		RangeEnd: "192.0.2.100",
+		Exclude:  []string{"192.0.2.20", "192.0.2.30-192.0.2.39", "192.0.2.64/27"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []*v4address.SelectedAddrRange{
+		{"192.0.2.11", "192.0.2.19"},
+		{"192.0.2.21", "192.0.2.29"},
+		{"192.0.2.40", "192.0.2.63"},
+		{"192.0.2.96", "192.0.2.100"},
+	}, ranges)
+
+	ranges, err = v4address.CandidateRanges("192.0.2.0", "255.255.255.0", &v4address.Allocation{Offset: 300})
+	require.NoError(t, err)
+	assert.Empty(t, ranges)
+
+	_, err = v4address.CandidateRanges("192.0.2.0", "255.255.255.0", &v4address.Allocation{Exclude: []string{"192.0.2.9-192.0.2.1"}})
+	require.ErrorIs(t, err, v4address.ErrInvalidAddressRange)
+}
+
+func TestValidateAddressRange(t *testing.T) {
+	require.NoError(t, v4address.ValidateAddressRange("192.0.2.1"))
+	require.NoError(t, v4address.ValidateAddressRange("192.0.2.1-192.0.2.10"))
+	require.NoError(t, v4address.ValidateAddressRange("192.0.2.0/28"))
+	require.Error(t, v4address.ValidateAddressRange("192.0.2"))
+	require.Error(t, v4address.ValidateAddressRange("2001:db8::/64"))
+}
+
+func TestSelectWithAllocation_Highest(t *testing.T) {
+	c, cleanup := test.GetTestClient(t)
+	defer cleanup()
+
+	var requested []string
+
+	httpmock.RegisterResponder("PUT", test.QIPServer+"/api/v1/"+test.QIPOrg+"/selectedv4address/192.0.2.0.json",
+		func(req *http.Request) (*http.Response, error) {
+			body := struct {
+				AddrRange []*v4address.SelectedAddrRange `json:"addrRange"`
+			}{}
+			if err := json.NewDecoder(req.Body).Decode(&body); err != nil || len(body.AddrRange) != 1 {
+				return httpmock.NewStringResponse(400, ""), nil //nolint:nilerr
+			}
+
+			addr := body.AddrRange[0].StartAddress
+			requested = append(requested, addr)
+
+			// Only .252 is still free
+			if addr != "192.0.2.252" {
+				return httpmock.NewStringResponse(400, `{"error":"no free address"}`), nil
+			}
+
+			return httpmock.NewStringResponse(200, `{"objectAddr":"`+addr+`"}`), nil
+		})
+
+	addr, err := v4address.SelectWithAllocation(c, "192.0.2.0", "255.255.255.0", &v4address.Allocation{
+		Strategy: v4address.AllocationHighest,
+		Exclude:  []string{"192.0.2.253"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "192.0.2.252", addr)
+	assert.Equal(t, []string{"192.0.2.254", "192.0.2.252"}, requested)
+}
+
+func TestSelectWithAllocation_Offset(t *testing.T) {
+	c, cleanup := test.GetTestClient(t)
+	defer cleanup()
+
+	var requested []string
+
+	httpmock.RegisterResponder("PUT", test.QIPServer+"/api/v1/"+test.QIPOrg+"/selectedv4address/192.0.2.0.json",
+		func(req *http.Request) (*http.Response, error) {
+			body := struct {
+				AddrRange []*v4address.SelectedAddrRange `json:"addrRange"`
+			}{}
+			if err := json.NewDecoder(req.Body).Decode(&body); err != nil || len(body.AddrRange) != 1 {
+				return httpmock.NewStringResponse(400, ""), nil //nolint:nilerr
+			}
+
+			addr := body.AddrRange[0].StartAddress
+			requested = append(requested, addr)
+
+			// .11 is the first host after the offset, but already in use
+			if addr == "192.0.2.11" {
+				return httpmock.NewStringResponse(400, `{"error":"no free address"}`), nil
+			}
+
+			return httpmock.NewStringResponse(200, `{"objectAddr":"`+addr+`"}`), nil
+		})
+
+	addr, err := v4address.SelectWithAllocation(c, "192.0.2.0", "255.255.255.0", &v4address.Allocation{
+		Strategy: v4address.AllocationOffset,
+		Offset:   10,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "192.0.2.12", addr)
+	assert.Equal(t, []string{"192.0.2.11", "192.0.2.12"}, requested)
+
+	_, err = v4address.SelectWithAllocation(c, "192.0.2.0", "255.255.255.0", &v4address.Allocation{Strategy: "first"})
+	require.ErrorIs(t, err, v4address.ErrUnknownAllocationStrategy)
+}
+
+func TestSelectWithAllocation_Lowest(t *testing.T) {
+	c, cleanup := test.GetTestClient(t)
+	defer cleanup()
+
+	var requested []string
+
+	httpmock.RegisterResponder("PUT", test.QIPServer+"/api/v1/"+test.QIPOrg+"/selectedv4address/192.0.2.0.json",
+		func(req *http.Request) (*http.Response, error) {
+			body := struct {
+				AddrRange []*v4address.SelectedAddrRange `json:"addrRange"`
+			}{}
+			if err := json.NewDecoder(req.Body).Decode(&body); err != nil || len(body.AddrRange) != 1 {
+				return httpmock.NewStringResponse(400, ""), nil //nolint:nilerr
+			}
+
+			addr := body.AddrRange[0].StartAddress
+			requested = append(requested, addr)
+
+			if addr == "192.0.2.1" {
+				return httpmock.NewStringResponse(409, `{"error":"address is in use"}`), nil
+			}
+
+			return httpmock.NewStringResponse(200, `{"objectAddr":"`+addr+`"}`), nil
+		})
+
+	addr, err := v4address.SelectWithAllocation(c, "192.0.2.0", "255.255.255.0", &v4address.Allocation{
+		Strategy: v4address.AllocationLowest,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "192.0.2.2", addr)
+	assert.Equal(t, []string{"192.0.2.1", "192.0.2.2"}, requested)
+}
+
+func TestSelectWithAllocation_Errors(t *testing.T) {
+	c, cleanup := test.GetTestClient(t)
+	defer cleanup()
+
+	url := test.QIPServer + "/api/v1/" + test.QIPOrg + "/selectedv4address/192.0.2.0.json"
+
+	// An outage is returned right away instead of probing the next address
+	httpmock.RegisterResponder("PUT", url, httpmock.NewStringResponder(503, `{"error":"maintenance"}`))
+
+	_, err := v4address.SelectWithAllocation(c, "192.0.2.0", "255.255.255.0", &v4address.Allocation{
+		Strategy: v4address.AllocationHighest,
+	})
+	require.Error(t, err)
+	require.NotErrorIs(t, err, v4address.ErrNoCandidateAddress)
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+
+	// More candidates than probes
+	httpmock.RegisterResponder("PUT", url, httpmock.NewStringResponder(400, `{"error":"no free address"}`))
+
+	_, err = v4address.SelectWithAllocation(c, "192.0.2.0", "255.255.254.0", &v4address.Allocation{
+		Strategy: v4address.AllocationRandom,
+	})
+	require.ErrorIs(t, err, v4address.ErrProbeLimitReached)
+	assert.Contains(t, err.Error(), "tried 256 of 510 candidate addresses")
+}
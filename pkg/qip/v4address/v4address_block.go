@@ -0,0 +1,261 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v4address
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip"
+)
+
+var (
+	ErrNoFreeBlock      = errors.New("no contiguous block of free addresses found")
+	ErrInvalidBlockSize = errors.New("block size must be at least 1")
+	ErrInvalidAlignment = errors.New("alignment must be a power of two")
+)
+
+// IsFree checks if no object exists for an address.
+//
+// Selected addresses are kept as objects without a name, so they are not free, see Status.
+func IsFree(client *qip.Client, address string) (bool, error) {
+	_, err := Load(client, address)
+	if err == nil {
+		return false, nil
+	}
+
+	var notFoundErr *qip.HTTPNotFoundError
+	if errors.As(err, &notFoundErr) {
+		return true, nil
+	}
+
+	return false, err
+}
+
+// FindFreeBlock searches the subnet for size consecutive addresses without an object.
+//
+// The first address of the block is a multiple of alignment, use 0 or 1 for no alignment.
+// The allocation limits the candidate addresses, the strategy is ignored.
+// Nothing is reserved, use SelectBlock when the addresses are going to be created.
+func FindFreeBlock(client *qip.Client, subnet, mask string, size, alignment int, alloc *Allocation) ([]string, error) {
+	return searchBlock(client, subnet, mask, size, alignment, alloc, false)
+}
+
+// SelectBlock searches the subnet like FindFreeBlock, and reserves every address of the block as selected address.
+//
+// Reserving each candidate prevents parallel runs from picking the same block, a block with an address that
+// can not be selected is released again and the search continues behind it.
+// The addresses must be created with CreateSelectedBlock, or freed with ReleaseBlock.
+func SelectBlock(client *qip.Client, subnet, mask string, size, alignment int, alloc *Allocation) ([]string, error) {
+	return searchBlock(client, subnet, mask, size, alignment, alloc, true)
+}
+
+func searchBlock(client *qip.Client, subnet, mask string, size, alignment int, alloc *Allocation, reserve bool) ([]string, error) {
+	if size < 1 {
+		return nil, ErrInvalidBlockSize
+	}
+
+	if alignment < 1 {
+		alignment = 1
+	} else if alignment&(alignment-1) != 0 {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidAlignment, alignment)
+	}
+
+	ranges, err := candidateRanges(subnet, mask, alloc)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range ranges {
+		start := alignUp(uint64(r.first), uint64(alignment))
+
+		for start+uint64(size)-1 <= uint64(r.last) {
+			used, err := lastUsedAddress(client, uint32(start), size)
+			if err != nil {
+				return nil, err
+			}
+
+			if used == nil && reserve {
+				used, err = reserveBlock(client, subnet, uint32(start), size)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			if used == nil {
+				return BlockAddresses(uint32ToIP(uint32(start)), size)
+			}
+
+			// Continue behind the address that is in use
+			start = alignUp(uint64(*used)+1, uint64(alignment))
+		}
+	}
+
+	return nil, ErrNoFreeBlock
+}
+
+// BlockAddresses returns size consecutive addresses starting with first.
+func BlockAddresses(first string, size int) ([]string, error) {
+	start, err := ipToUint32(first)
+	if err != nil {
+		return nil, err
+	}
+
+	if size < 1 {
+		return nil, ErrInvalidBlockSize
+	}
+
+	if uint64(start)+uint64(size)-1 > uint64(^uint32(0)) {
+		return nil, fmt.Errorf("%w: %s+%d", ErrInvalidAddressRange, first, size)
+	}
+
+	addresses := make([]string, 0, size)
+	for i := 0; i < size; i++ {
+		addresses = append(addresses, uint32ToIP(start+uint32(i)))
+	}
+
+	return addresses, nil
+}
+
+// CreateBlock creates all objects, if one fails the objects created before are deleted again.
+func CreateBlock(client *qip.Client, addrs []*V4Address) error {
+	return createBlock(client, addrs, false)
+}
+
+// CreateSelectedBlock converts the addresses reserved by SelectBlock into objects.
+//
+// If one fails, the objects created before are deleted and the remaining selections are released.
+func CreateSelectedBlock(client *qip.Client, addrs []*V4Address) error {
+	return createBlock(client, addrs, true)
+}
+
+func createBlock(client *qip.Client, addrs []*V4Address, selected bool) error {
+	for i, addr := range addrs {
+		var err error
+
+		if selected {
+			err = Update(client, addr)
+		} else {
+			err = Create(client, addr)
+		}
+
+		if err == nil {
+			continue
+		}
+
+		for _, created := range addrs[:i] {
+			if deleteErr := Delete(client, created.ObjectAddr); deleteErr != nil {
+				err = errors.Join(err, fmt.Errorf("could not roll back %s: %w", created.ObjectAddr, deleteErr))
+			}
+		}
+
+		if selected {
+			remaining := make([]string, 0, len(addrs)-i)
+			for _, pending := range addrs[i:] {
+				remaining = append(remaining, pending.ObjectAddr)
+			}
+
+			if releaseErr := ReleaseBlock(client, remaining); releaseErr != nil {
+				err = errors.Join(err, releaseErr)
+			}
+		}
+
+		return fmt.Errorf("could not create block at %s: %w", addr.ObjectAddr, err)
+	}
+
+	return nil
+}
+
+// ReleaseBlock frees all addresses reserved by SelectBlock.
+func ReleaseBlock(client *qip.Client, addresses []string) error {
+	var err error
+
+	for _, address := range addresses {
+		if releaseErr := DeleteSelected(client, address); releaseErr != nil {
+			err = errors.Join(err, fmt.Errorf("could not release %s: %w", address, releaseErr))
+		}
+	}
+
+	return err
+}
+
+// reserveBlock selects each address of the block on its own, and returns the address that is not free.
+//
+// On a refused selection the addresses reserved before are released again.
+func reserveBlock(client *qip.Client, subnet string, start uint32, size int) (*uint32, error) {
+	reserved := make([]string, 0, size)
+
+	for i := 0; i < size; i++ {
+		addr := start + uint32(i)
+		address := uint32ToIP(addr)
+
+		selected, err := CreateSelectedInRanges(client, subnet, []*SelectedAddrRange{{address, address}})
+		if err == nil && selected != address {
+			// QIP picked another address, treat the requested one as in use
+			reserved = append(reserved, selected)
+			err = fmt.Errorf("%w: %s", ErrNoSelection, address)
+		}
+
+		if err == nil {
+			reserved = append(reserved, selected)
+
+			continue
+		}
+
+		if releaseErr := ReleaseBlock(client, reserved); releaseErr != nil {
+			return nil, errors.Join(err, releaseErr)
+		}
+
+		if !isNotFreeError(err) {
+			return nil, err
+		}
+
+		return &addr, nil
+	}
+
+	return nil, nil //nolint:nilnil
+}
+
+// lastUsedAddress checks the addresses starting at start, and returns the last address of the block that is in use.
+//
+// Checking from the end allows the search to skip as far as possible.
+func lastUsedAddress(client *qip.Client, start uint32, size int) (*uint32, error) {
+	for i := size - 1; i >= 0; i-- {
+		addr := start + uint32(i)
+
+		free, err := IsFree(client, uint32ToIP(addr))
+		if err != nil {
+			return nil, err
+		}
+
+		if !free {
+			return &addr, nil
+		}
+	}
+
+	return nil, nil //nolint:nilnil
+}
+
+func alignUp(value, alignment uint64) uint64 {
+	if remainder := value % alignment; remainder != 0 {
+		return value + alignment - remainder
+	}
+
+	return value
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v4address_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/test"
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/v4address"
+)
+
+func TestFindFreeBlock(t *testing.T) {
+	c, cleanup := test.GetTestClient(t)
+	defer cleanup()
+
+	used := map[string]bool{
+		"192.0.2.1":  true,
+		"192.0.2.2":  true,
+		"192.0.2.10": true,
+	}
+
+	httpmock.RegisterRegexpResponder("GET", regexp.MustCompile(`/v4address/([0-9.]+)\.json$`),
+		func(req *http.Request) (*http.Response, error) {
+			addr := httpmock.MustGetSubmatch(req, 1)
+			if used[addr] {
+				return httpmock.NewStringResponse(200, `{"objectAddr":"`+addr+`"}`), nil
+			}
+
+			return httpmock.NewStringResponse(404, ""), nil
+		})
+
+	addrs, err := v4address.FindFreeBlock(c, "192.0.2.0", "255.255.255.0", 4, 0, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"192.0.2.3", "192.0.2.4", "192.0.2.5", "192.0.2.6"}, addrs)
+
+	addrs, err = v4address.FindFreeBlock(c, "192.0.2.0", "255.255.255.0", 4, 8, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"192.0.2.16", "192.0.2.17", "192.0.2.18", "192.0.2.19"}, addrs)
+
+	_, err = v4address.FindFreeBlock(c, "192.0.2.0", "255.255.255.0", 4, 3, nil)
+	require.ErrorIs(t, err, v4address.ErrInvalidAlignment)
+
+	_, err = v4address.FindFreeBlock(c, "192.0.2.0", "255.255.255.248", 8, 0, nil)
+	require.ErrorIs(t, err, v4address.ErrNoFreeBlock)
+}
+
+func TestSelectBlock(t *testing.T) {
+	c, cleanup := test.GetTestClient(t)
+	defer cleanup()
+
+	var requested []string
+
+	httpmock.RegisterRegexpResponder("GET", regexp.MustCompile(`/v4address/([0-9.]+)\.json$`),
+		httpmock.NewStringResponder(404, ""))
+	httpmock.RegisterResponder("PUT", test.QIPServer+"/api/v1/"+test.QIPOrg+"/selectedv4address/192.0.2.0.json",
+		func(req *http.Request) (*http.Response, error) {
+			body := struct {
+				AddrRange []*v4address.SelectedAddrRange `json:"addrRange"`
+			}{}
+			if err := json.NewDecoder(req.Body).Decode(&body); err != nil || len(body.AddrRange) != 1 {
+				return httpmock.NewStringResponse(400, ""), nil //nolint:nilerr
+			}
+
+			addr := body.AddrRange[0].StartAddress
+			requested = append(requested, addr)
+
+			if addr == "192.0.2.3" {
+				// Selected by a parallel run
+				return httpmock.NewStringResponse(409, `{"error":"address is in use"}`), nil
+			}
+
+			return httpmock.NewStringResponse(200, `{"objectAddr":"`+addr+`"}`), nil
+		})
+	httpmock.RegisterRegexpResponder("DELETE", regexp.MustCompile(`/selectedv4address/([0-9.]+)/$`),
+		httpmock.NewStringResponder(200, ""))
+
+	addrs, err := v4address.SelectBlock(c, "192.0.2.0", "255.255.255.0", 3, 0, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"192.0.2.4", "192.0.2.5", "192.0.2.6"}, addrs)
+	assert.Equal(t, []string{"192.0.2.1", "192.0.2.2", "192.0.2.3", "192.0.2.4", "192.0.2.5", "192.0.2.6"}, requested)
+
+	info := httpmock.GetCallCountInfo()
+	selectedURL := test.QIPServer + "/api/v1/" + test.QIPOrg + "/selectedv4address/"
+	assert.Equal(t, 1, info["DELETE "+selectedURL+"192.0.2.1/"])
+	assert.Equal(t, 1, info["DELETE "+selectedURL+"192.0.2.2/"])
+	assert.Equal(t, 0, info["DELETE "+selectedURL+"192.0.2.4/"])
+}
+
+func TestCreateSelectedBlock(t *testing.T) {
+	c, cleanup := test.GetTestClient(t)
+	defer cleanup()
+
+	httpmock.RegisterResponder("PUT", test.QIPServer+"/api/v1/"+test.QIPOrg+"/v4address",
+		httpmock.NewStringResponder(200, "").Then(httpmock.NewStringResponder(400, `{"error":"invalid"}`)))
+	httpmock.RegisterResponder("DELETE", test.QIPServer+"/api/v1/"+test.QIPOrg+"/v4address/192.0.2.3/",
+		httpmock.NewStringResponder(200, ""))
+	httpmock.RegisterRegexpResponder("DELETE", regexp.MustCompile(`/selectedv4address/([0-9.]+)/$`),
+		httpmock.NewStringResponder(200, ""))
+
+	err := v4address.CreateSelectedBlock(c, []*v4address.V4Address{
+		{ObjectAddr: "192.0.2.3", SubnetAddr: "192.0.2.0", ObjectName: "node-1"},
+		{ObjectAddr: "192.0.2.4", SubnetAddr: "192.0.2.0", ObjectName: "node-2"},
+		{ObjectAddr: "192.0.2.5", SubnetAddr: "192.0.2.0", ObjectName: "node-3"},
+	})
+	require.Error(t, err)
+
+	info := httpmock.GetCallCountInfo()
+	selectedURL := test.QIPServer + "/api/v1/" + test.QIPOrg + "/selectedv4address/"
+	assert.Equal(t, 1, info["DELETE "+test.QIPServer+"/api/v1/"+test.QIPOrg+"/v4address/192.0.2.3/"])
+	assert.Equal(t, 0, info["DELETE "+selectedURL+"192.0.2.3/"])
+	assert.Equal(t, 1, info["DELETE "+selectedURL+"192.0.2.4/"])
+	assert.Equal(t, 1, info["DELETE "+selectedURL+"192.0.2.5/"])
+}
+
+func TestCreateBlock(t *testing.T) {
+	c, cleanup := test.GetTestClient(t)
+	defer cleanup()
+
+	httpmock.RegisterResponder("POST", test.QIPServer+"/api/v1/"+test.QIPOrg+"/v4address",
+		httpmock.NewStringResponder(200, "").Then(httpmock.NewStringResponder(409, `{"error":"duplicate"}`)))
+	httpmock.RegisterResponder("DELETE", test.QIPServer+"/api/v1/"+test.QIPOrg+"/v4address/192.0.2.3/",
+		httpmock.NewStringResponder(200, ""))
+
+	err := v4address.CreateBlock(c, []*v4address.V4Address{
+		{ObjectAddr: "192.0.2.3", SubnetAddr: "192.0.2.0", ObjectName: "node-1"},
+		{ObjectAddr: "192.0.2.4", SubnetAddr: "192.0.2.0", ObjectName: "node-2"},
+	})
+	require.Error(t, err)
+
+	info := httpmock.GetCallCountInfo()
+	assert.Equal(t, 1, info["DELETE "+test.QIPServer+"/api/v1/"+test.QIPOrg+"/v4address/192.0.2.3/"])
+}
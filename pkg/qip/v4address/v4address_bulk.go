@@ -0,0 +1,183 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v4address
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip"
+)
+
+// BulkOperation is the action executed for a BulkItem.
+type BulkOperation string
+
+const (
+	BulkCreate BulkOperation = "create"
+	BulkUpdate BulkOperation = "update"
+	BulkDelete BulkOperation = "delete"
+)
+
+// DefaultBulkWorkers is the number of parallel requests used when BulkOptions.Workers is not set.
+const DefaultBulkWorkers = 4
+
+var ErrUnknownBulkOperation = errors.New("unknown bulk operation")
+
+// BulkItem is a single change executed by Bulk.
+type BulkItem struct {
+	Operation BulkOperation
+	// Address is the object to create or update, for deletes only ObjectAddr is used.
+	Address *V4Address
+}
+
+// BulkOptions control the execution of Bulk.
+type BulkOptions struct {
+	// Workers limits the number of parallel requests to QIP.
+	Workers int
+	// StopOnError skips all items not started yet after the first failure.
+	StopOnError bool
+}
+
+// BulkResult reports the outcome of a BulkItem, results have the same order as the items.
+type BulkResult struct {
+	Item *BulkItem
+	// Unchanged is set when the item was already in the requested state,
+	// i.e. the object to create already existed or the object to delete was already gone.
+	Unchanged bool
+	// Skipped is set when the item was not executed because of StopOnError.
+	Skipped bool
+	Err     error
+}
+
+// Bulk executes create, update and delete operations for many objects in parallel.
+//
+// All items are executed, unless StopOnError is set. A result is returned for every item,
+// the error joins the errors of all failed items.
+func Bulk(client *qip.Client, items []*BulkItem, opts *BulkOptions) ([]*BulkResult, error) {
+	if opts == nil {
+		opts = &BulkOptions{}
+	}
+
+	workers := opts.Workers
+	if workers < 1 {
+		workers = DefaultBulkWorkers
+	}
+
+	var (
+		results = make([]*BulkResult, len(items))
+		queue   = make(chan int)
+		failed  atomic.Bool
+		wg      sync.WaitGroup
+	)
+
+	for w := 0; w < min(workers, len(items)); w++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range queue {
+				if opts.StopOnError && failed.Load() {
+					results[i] = &BulkResult{Item: items[i], Skipped: true}
+
+					continue
+				}
+
+				results[i] = bulkExecute(client, items[i])
+				if results[i].Err != nil {
+					failed.Store(true)
+				}
+			}
+		}()
+	}
+
+	for i := range items {
+		queue <- i
+	}
+
+	close(queue)
+	wg.Wait()
+
+	var errs []error
+
+	for _, result := range results {
+		if result.Err != nil {
+			errs = append(errs, result.Err)
+		}
+	}
+
+	return results, errors.Join(errs...)
+}
+
+// bulkExecute runs a single item, existing objects on create and missing objects on delete are no error.
+func bulkExecute(client *qip.Client, item *BulkItem) *BulkResult {
+	result := &BulkResult{Item: item}
+
+	if item.Address == nil {
+		result.Err = fmt.Errorf("%s: %w", item.Operation, ErrBothAddrRequired)
+
+		return result
+	}
+
+	var err error
+
+	switch item.Operation {
+	case BulkCreate:
+		err = Create(client, item.Address)
+		if err != nil && bulkExists(client, item.Address) {
+			result.Unchanged = true
+			err = nil
+		}
+	case BulkUpdate:
+		err = Update(client, item.Address)
+	case BulkDelete:
+		err = Delete(client, item.Address.ObjectAddr)
+
+		var notFoundErr *qip.HTTPNotFoundError
+		if errors.As(err, &notFoundErr) {
+			result.Unchanged = true
+			err = nil
+		}
+	default:
+		err = fmt.Errorf("%w: %s", ErrUnknownBulkOperation, item.Operation)
+	}
+
+	if err != nil {
+		result.Err = fmt.Errorf("%s %s: %w", item.Operation, item.Address.ObjectAddr, err)
+	}
+
+	return result
+}
+
+// bulkExists checks if an object with the same address and name is already present in QIP.
+func bulkExists(client *qip.Client, addr *V4Address) bool {
+	if addr.ObjectAddr == "" {
+		return false
+	}
+
+	existing, err := Load(client, addr.ObjectAddr)
+	if err != nil {
+		return false
+	}
+
+	return strings.EqualFold(existing.ObjectName, addr.ObjectName)
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v4address_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/test"
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/v4address"
+)
+
+func TestBulk(t *testing.T) {
+	c, cleanup := test.GetTestClient(t)
+	defer cleanup()
+
+	baseURL := test.QIPServer + "/api/v1/" + test.QIPOrg
+
+	httpmock.RegisterResponder("POST", baseURL+"/v4address",
+		func(req *http.Request) (*http.Response, error) {
+			var addr v4address.V4Address
+			if err := json.NewDecoder(req.Body).Decode(&addr); err != nil {
+				return httpmock.NewStringResponse(400, ""), nil //nolint:nilerr
+			}
+
+			if addr.ObjectAddr == "192.0.2.1" {
+				return httpmock.NewStringResponse(200, ""), nil
+			}
+
+			return httpmock.NewStringResponse(400, `{"error":"exists"}`), nil
+		})
+	httpmock.RegisterResponder("GET", baseURL+"/v4address/192.0.2.2.json",
+		httpmock.NewStringResponder(200, `{"objectAddr":"192.0.2.2","objectName":"host-2"}`))
+	httpmock.RegisterResponder("GET", baseURL+"/v4address/192.0.2.3.json",
+		httpmock.NewStringResponder(200, `{"objectAddr":"192.0.2.3","objectName":"someone-else"}`))
+	httpmock.RegisterResponder("DELETE", baseURL+"/v4address/192.0.2.4/",
+		httpmock.NewStringResponder(404, ""))
+
+	items := []*v4address.BulkItem{
+		{Operation: v4address.BulkCreate, Address: &v4address.V4Address{
+			ObjectAddr: "192.0.2.1", SubnetAddr: "192.0.2.0", ObjectName: "host-1",
+		}},
+		{Operation: v4address.BulkCreate, Address: &v4address.V4Address{
+			ObjectAddr: "192.0.2.2", SubnetAddr: "192.0.2.0", ObjectName: "host-2",
+		}},
+		{Operation: v4address.BulkCreate, Address: &v4address.V4Address{
+			ObjectAddr: "192.0.2.3", SubnetAddr: "192.0.2.0", ObjectName: "host-3",
+		}},
+		{Operation: v4address.BulkDelete, Address: &v4address.V4Address{ObjectAddr: "192.0.2.4"}},
+	}
+
+	results, err := v4address.Bulk(c, items, &v4address.BulkOptions{Workers: 2})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "create 192.0.2.3")
+	require.Len(t, results, len(items))
+
+	assert.NoError(t, results[0].Err)
+	assert.False(t, results[0].Unchanged)
+	assert.NoError(t, results[1].Err)
+	assert.True(t, results[1].Unchanged)
+	assert.Error(t, results[2].Err)
+	assert.NoError(t, results[3].Err)
+	assert.True(t, results[3].Unchanged)
+}
+
+func TestBulk_StopOnError(t *testing.T) {
+	c, cleanup := test.GetTestClient(t)
+	defer cleanup()
+
+	baseURL := test.QIPServer + "/api/v1/" + test.QIPOrg
+
+	httpmock.RegisterResponder("DELETE", `=~^`+baseURL+`/v4address/`,
+		httpmock.NewStringResponder(500, ""))
+
+	items := []*v4address.BulkItem{
+		{Operation: v4address.BulkDelete, Address: &v4address.V4Address{ObjectAddr: "192.0.2.1"}},
+		{Operation: v4address.BulkDelete, Address: &v4address.V4Address{ObjectAddr: "192.0.2.2"}},
+		{Operation: v4address.BulkDelete, Address: &v4address.V4Address{ObjectAddr: "192.0.2.3"}},
+	}
+
+	results, err := v4address.Bulk(c, items, &v4address.BulkOptions{Workers: 1, StopOnError: true})
+	require.Error(t, err)
+	require.Len(t, results, len(items))
+
+	assert.Error(t, results[0].Err)
+	assert.True(t, results[1].Skipped)
+	assert.True(t, results[2].Skipped)
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+}
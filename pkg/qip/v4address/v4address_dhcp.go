@@ -0,0 +1,96 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v4address
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// Values for V4Address.DynamicConfig.
+const (
+	DynamicConfigStatic     = "Static"
+	DynamicConfigManualDHCP = "Manual DHCP"
+	DynamicConfigReserved   = "Reserved"
+)
+
+// macAddressLength is the number of bytes of an Ethernet MAC address.
+const macAddressLength = 6
+
+var ErrInvalidMACAddress = errors.New("not a valid MAC address")
+
+// DHCPReservationFields is the field mask for Patch covering all fields of a DHCP reservation.
+var DHCPReservationFields = []string{
+	"dynamicConfig", "macAddr", "clientId", "dhcpServer", "dhcpOptionTemplate", "dhcpPolicyTemplate", "leaseTime",
+}
+
+// IsDHCPReservation checks if the object is configured for a fixed DHCP assignment.
+func (addr *V4Address) IsDHCPReservation() bool {
+	switch addr.DynamicConfig {
+	case DynamicConfigManualDHCP, DynamicConfigReserved:
+		return true
+	}
+
+	return false
+}
+
+// SetDHCPReservation turns the object into a DHCP reservation for a MAC address or client ID.
+func (addr *V4Address) SetDHCPReservation(dynamicConfig, macAddr, clientID string) {
+	addr.DynamicConfig = dynamicConfig
+	addr.MacAddr = macAddr
+	addr.ClientId = clientID
+}
+
+// ClearDHCPReservation turns the object back into a static object and removes all DHCP settings.
+func (addr *V4Address) ClearDHCPReservation() {
+	addr.DynamicConfig = DynamicConfigStatic
+	addr.MacAddr = ""
+	addr.ClientId = ""
+	addr.DhcpServer = ""
+	addr.DhcpOptionTemplate = ""
+	addr.DhcpPolicyTemplate = ""
+	addr.LeaseTime = ""
+}
+
+// NormalizeMACAddress returns an Ethernet MAC address in lower case hex separated by colons.
+//
+// Accepted formats are separated by colons or hyphens (00:11:22:33:44:55), dotted (0011.2233.4455)
+// or plain hex (001122334455).
+func NormalizeMACAddress(value string) (string, error) {
+	value = strings.TrimSpace(value)
+
+	if len(value) == hex.EncodedLen(macAddressLength) {
+		data, err := hex.DecodeString(value)
+		if err != nil {
+			return "", fmt.Errorf("%w: %s", ErrInvalidMACAddress, value)
+		}
+
+		return net.HardwareAddr(data).String(), nil
+	}
+
+	mac, err := net.ParseMAC(value)
+	if err != nil || len(mac) != macAddressLength {
+		return "", fmt.Errorf("%w: %s", ErrInvalidMACAddress, value)
+	}
+
+	return mac.String(), nil
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v4address_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/v4address"
+)
+
+func TestNormalizeMACAddress(t *testing.T) {
+	for _, value := range []string{
+		"00:11:22:AA:bb:cc",
+		"00-11-22-aa-bb-cc",
+		"0011.22aa.bbcc",
+		"001122AABBCC",
+	} {
+		mac, err := v4address.NormalizeMACAddress(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, "00:11:22:aa:bb:cc", mac, value)
+	}
+
+	for _, value := range []string{"", "00:11:22", "00112233445g", "00:11:22:33:44:55:66:77"} {
+		_, err := v4address.NormalizeMACAddress(value)
+		require.ErrorIs(t, err, v4address.ErrInvalidMACAddress, value)
+	}
+}
+
+func TestV4Address_DHCPReservation(t *testing.T) {
+	addr := &v4address.V4Address{DynamicConfig: v4address.DynamicConfigStatic}
+	assert.False(t, addr.IsDHCPReservation())
+
+	addr.SetDHCPReservation(v4address.DynamicConfigManualDHCP, "00:11:22:aa:bb:cc", "")
+	addr.DhcpServer = "dhcp1.example.com"
+	assert.True(t, addr.IsDHCPReservation())
+
+	addr.ClearDHCPReservation()
+	assert.False(t, addr.IsDHCPReservation())
+	assert.Empty(t, addr.MacAddr)
+	assert.Empty(t, addr.DhcpServer)
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v4address
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip"
+)
+
+// ExpiredDateLayout is the date format QIP uses for V4Address.ExpiredDate.
+const ExpiredDateLayout = "01/02/2006"
+
+// expiredDateLayouts are accepted when reading an ExpiredDate.
+var expiredDateLayouts = []string{ //nolint:gochecknoglobals
+	ExpiredDateLayout,
+	time.DateOnly,
+	time.RFC3339,
+}
+
+var ErrInvalidExpiredDate = errors.New("could not parse expiredDate")
+
+// ExpiresAt returns the expiry date of the object in UTC, ok is false when the object does not expire.
+func (addr *V4Address) ExpiresAt() (expires time.Time, ok bool, err error) {
+	value := strings.TrimSpace(addr.ExpiredDate)
+	if value == "" || value == "None" {
+		return time.Time{}, false, nil
+	}
+
+	for _, layout := range expiredDateLayouts {
+		expires, err = time.ParseInLocation(layout, value, time.UTC)
+		if err == nil {
+			return expires.UTC(), true, nil
+		}
+	}
+
+	return time.Time{}, false, fmt.Errorf("%w: %s", ErrInvalidExpiredDate, value)
+}
+
+// SetExpiresAt sets the expiry date of the object, QIP only stores the day. A zero time removes the expiry.
+func (addr *V4Address) SetExpiresAt(expires time.Time) {
+	if expires.IsZero() {
+		addr.ExpiredDate = ""
+
+		return
+	}
+
+	addr.ExpiredDate = expires.UTC().Format(ExpiredDateLayout)
+}
+
+// ListExpiring returns objects matching the query, which expire before the end of the window.
+//
+// Objects that are already expired are included. Objects with an expiry date that can not be parsed
+// are skipped and returned as invalid, so one broken object does not hide the others.
+func ListExpiring(client *qip.Client, query *Query, now time.Time, window time.Duration) (expiring, invalid []*V4Address, err error) {
+	addrs, err := List(client, query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	deadline := now.Add(window)
+
+	for _, addr := range addrs {
+		expires, ok, err := addr.ExpiresAt()
+		if err != nil {
+			invalid = append(invalid, addr)
+
+			continue
+		}
+
+		if ok && !expires.After(deadline) {
+			expiring = append(expiring, addr)
+		}
+	}
+
+	return expiring, invalid, nil
+}
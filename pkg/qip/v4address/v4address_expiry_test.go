@@ -0,0 +1,88 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v4address_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/test"
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/v4address"
+)
+
+func TestV4Address_ExpiresAt(t *testing.T) {
+	addr := &v4address.V4Address{}
+
+	_, ok, err := addr.ExpiresAt()
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	addr.SetExpiresAt(time.Date(2024, 3, 31, 15, 4, 5, 0, time.FixedZone("CEST", 2*60*60)))
+	assert.Equal(t, "03/31/2024", addr.ExpiredDate)
+
+	expires, ok, err := addr.ExpiresAt()
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC), expires)
+
+	addr.ExpiredDate = "2024-03-31"
+	expires, _, err = addr.ExpiresAt()
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC), expires)
+
+	addr.ExpiredDate = "tomorrow"
+	_, _, err = addr.ExpiresAt()
+	require.ErrorIs(t, err, v4address.ErrInvalidExpiredDate)
+}
+
+func TestListExpiring(t *testing.T) {
+	c, cleanup := test.GetTestClient(t)
+	defer cleanup()
+
+	httpmock.RegisterResponder("GET", test.QIPServer+"/api/v1/"+test.QIPOrg+"/v4address.json",
+		httpmock.NewStringResponder(200, `{"list":[
+			{"objectAddr":"192.0.2.50","expiredDate":"03/01/2024"},
+			{"objectAddr":"192.0.2.51","expiredDate":"03/20/2024"},
+			{"objectAddr":"192.0.2.52","expiredDate":"05/01/2024"},
+			{"objectAddr":"192.0.2.53"},
+			{"objectAddr":"192.0.2.54","expiredDate":"04/09/2024"},
+			{"objectAddr":"192.0.2.55","expiredDate":"04/10/2024"},
+			{"objectAddr":"192.0.2.56","expiredDate":"31.03.2024"}
+		]}`))
+
+	now := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	addrs, invalid, err := v4address.ListExpiring(c, nil, now, 30*24*time.Hour)
+	require.NoError(t, err)
+
+	// The end of the window is included, the day after is not
+	if assert.Len(t, addrs, 3) {
+		assert.Equal(t, "192.0.2.50", addrs[0].ObjectAddr)
+		assert.Equal(t, "192.0.2.51", addrs[1].ObjectAddr)
+		assert.Equal(t, "192.0.2.54", addrs[2].ObjectAddr)
+	}
+
+	if assert.Len(t, invalid, 1) {
+		assert.Equal(t, "192.0.2.56", invalid[0].ObjectAddr)
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v4address
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip"
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/rest"
+)
+
+// Query filters objects for List, empty fields are not filtered.
+type Query struct {
+	SubnetAddr string
+	ObjectName string
+	DomainName string
+	// IncludeTombstoned also returns deleted objects, that are still kept by QIP as a tombstone.
+	IncludeTombstoned bool
+}
+
+type listResult struct {
+	List []*V4Address
+}
+
+// List returns all objects of the organization matching the query.
+func List(client *qip.Client, query *Query) ([]*V4Address, error) {
+	values := url.Values{}
+
+	if query != nil {
+		setQueryValue(values, "subnetAddress", query.SubnetAddr)
+		setQueryValue(values, "name", query.ObjectName)
+		setQueryValue(values, "domainName", query.DomainName)
+	}
+
+	listURL := client.APITenantURL("v4address.json")
+	if len(values) > 0 {
+		listURL += "?" + values.Encode()
+	}
+
+	request, err := rest.NewRequest("GET", listURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not build get request: %w", err)
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("could not list V4Address: %w", err)
+	}
+
+	var results listResult
+
+	err = rest.UnmarshalResponse(response, &results)
+	if err != nil {
+		return nil, fmt.Errorf("could not unmarshal JSON result: %w", err)
+	}
+
+	if query != nil && query.IncludeTombstoned {
+		return results.List, nil
+	}
+
+	addrs := make([]*V4Address, 0, len(results.List))
+
+	for _, addr := range results.List {
+		if !addr.IsTombstoned() {
+			addrs = append(addrs, addr)
+		}
+	}
+
+	return addrs, nil
+}
+
+func setQueryValue(values url.Values, key, value string) {
+	if value != "" {
+		values.Set(key, value)
+	}
+}
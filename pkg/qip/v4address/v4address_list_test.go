@@ -0,0 +1,46 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v4address_test
+
+import (
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/test"
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/v4address"
+)
+
+func TestList(t *testing.T) {
+	c, cleanup := test.GetTestClient(t)
+	defer cleanup()
+
+	httpmock.RegisterResponderWithQuery("GET", test.QIPServer+"/api/v1/"+test.QIPOrg+"/v4address.json",
+		"subnetAddress=192.0.2.0&name=test-host",
+		httpmock.NewStringResponder(200, `{"list":[{"objectAddr":"192.0.2.50","subnetAddr":"192.0.2.0","objectName":"test-host"}]}`))
+
+	addrs, err := v4address.List(c, &v4address.Query{SubnetAddr: "192.0.2.0", ObjectName: "test-host"})
+	require.NoError(t, err)
+
+	if assert.Len(t, addrs, 1) {
+		assert.Equal(t, "192.0.2.50", addrs[0].ObjectAddr)
+	}
+}
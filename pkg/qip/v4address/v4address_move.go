@@ -0,0 +1,208 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v4address
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip"
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/rr"
+)
+
+var ErrOldAddressRequired = errors.New("address of the object to move is required")
+
+// moveNameSuffix is appended to the name of the old object while it is moved.
+const moveNameSuffix = "-moving"
+
+// maxObjectNameLength is the maximum length of a hostname label.
+const maxObjectNameLength = 63
+
+// Move an object to a new address and/or subnet.
+//
+// The old object is renamed first and its unique values (MAC address, client ID and aliases) are removed,
+// so the copy can be created with them even when QIP checks for duplicates. The object is created at the new
+// address with all attributes copied except the ones assigned by QIP, its RRs are re-pointed to the new
+// address and the old address is freed afterwards.
+//
+// When newAddr is empty, a free address is selected in newSubnet. When newSubnet is empty, the subnet is kept.
+// If creating the copy or re-pointing a RR fails, all changes are rolled back.
+func Move(client *qip.Client, oldAddr, newAddr, newSubnet string) (*V4Address, error) {
+	if oldAddr == "" {
+		return nil, ErrOldAddressRequired
+	}
+
+	addr, err := Load(client, oldAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := rr.LoadAllForObject(client, oldAddr)
+	if err != nil {
+		return nil, fmt.Errorf("could not load RRs of %s: %w", oldAddr, err)
+	}
+
+	moved := movedCopy(addr, newAddr, newSubnet)
+	parked := parkedCopy(addr)
+
+	err = Update(client, parked)
+	if err != nil {
+		return nil, fmt.Errorf("could not rename %s before the move: %w", oldAddr, err)
+	}
+
+	err = moveCreate(client, moved)
+	if err != nil {
+		return nil, moveRestoreName(client, addr, err)
+	}
+
+	err = moveRecords(client, records, oldAddr, moved.ObjectAddr)
+	if err != nil {
+		if deleteErr := Delete(client, moved.ObjectAddr); deleteErr != nil {
+			err = errors.Join(err, fmt.Errorf("could not roll back new object %s: %w", moved.ObjectAddr, deleteErr))
+		}
+
+		return nil, moveRestoreName(client, addr, err)
+	}
+
+	err = Delete(client, oldAddr)
+	if err != nil {
+		return moved, fmt.Errorf("object was moved to %s, but the old address could not be freed: %w", moved.ObjectAddr, err)
+	}
+
+	return moved, nil
+}
+
+// movedCopy returns the object to create at the new address, without the identity and state QIP assigned
+// to the old object.
+func movedCopy(addr *V4Address, newAddr, newSubnet string) *V4Address {
+	moved := *addr
+	moved.ObjectAddr = newAddr
+
+	if newSubnet != "" {
+		moved.SubnetAddr = newSubnet
+	}
+
+	moved.NodeId = ""
+	moved.UniqueNodeId = ""
+	moved.Tombstoned = ""
+	moved.ExternalTimestamp = ""
+	moved.ManualFlag = ""
+	moved.LocalManualFlag = ""
+
+	return &moved
+}
+
+// parkedCopy returns the old object while it is moved, renamed and without the values QIP only allows once.
+func parkedCopy(addr *V4Address) *V4Address {
+	parked := *addr
+	parked.ObjectName = movingName(addr.ObjectName)
+	parked.Aliases = ""
+
+	if parked.IsDHCPReservation() {
+		// A reservation requires the MAC address or client ID
+		parked.ClearDHCPReservation()
+	}
+
+	parked.MacAddr = ""
+	parked.ClientId = ""
+
+	return &parked
+}
+
+// movingName returns the temporary name of an object while it is moved.
+func movingName(name string) string {
+	if len(name)+len(moveNameSuffix) > maxObjectNameLength {
+		name = name[:maxObjectNameLength-len(moveNameSuffix)]
+	}
+
+	return name + moveNameSuffix
+}
+
+// moveRestoreName renames the old object back after a failed move, and adds a failed rename to err.
+func moveRestoreName(client *qip.Client, addr *V4Address, err error) error {
+	if restoreErr := Update(client, addr); restoreErr != nil {
+		err = errors.Join(err, fmt.Errorf("could not restore the name of %s: %w", addr.ObjectAddr, restoreErr))
+	}
+
+	return err
+}
+
+// moveCreate creates the copied object, selecting a free address if none is set.
+func moveCreate(client *qip.Client, addr *V4Address) error {
+	if addr.ObjectAddr != "" {
+		return Create(client, addr)
+	}
+
+	selected, err := CreateSelected(client, addr.SubnetAddr, nil)
+	if err != nil {
+		return err
+	}
+
+	addr.ObjectAddr = selected
+
+	err = Update(client, addr)
+	if err != nil {
+		if deleteErr := DeleteSelected(client, selected); deleteErr != nil {
+			err = errors.Join(err, fmt.Errorf("could not release selected address %s: %w", selected, deleteErr))
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// moveRecords re-points the RRs to the new address, reverting already moved records on failure.
+func moveRecords(client *qip.Client, records []*rr.RR, oldAddr, newAddr string) error {
+	for i, record := range records {
+		movedRecord := *record
+		movedRecord.InfraAddr = newAddr
+
+		if movedRecord.Data1 == oldAddr {
+			// A records carry the address as data
+			movedRecord.Data1 = newAddr
+		}
+
+		err := rr.Update(client, record, &movedRecord)
+		if err == nil {
+			records[i] = &movedRecord
+
+			continue
+		}
+
+		err = fmt.Errorf("could not move RR %s: %w", record.Owner, err)
+
+		for _, done := range records[:i] {
+			reverted := *done
+			reverted.InfraAddr = oldAddr
+
+			if reverted.Data1 == newAddr {
+				reverted.Data1 = oldAddr
+			}
+
+			if revertErr := rr.Update(client, done, &reverted); revertErr != nil {
+				err = errors.Join(err, fmt.Errorf("could not roll back RR %s: %w", done.Owner, revertErr))
+			}
+		}
+
+		return err
+	}
+
+	return nil
+}
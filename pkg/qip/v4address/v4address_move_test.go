@@ -0,0 +1,171 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v4address_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/rr"
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/test"
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/v4address"
+)
+
+func TestMove(t *testing.T) {
+	c, cleanup := test.GetTestClient(t)
+	defer cleanup()
+
+	baseURL := test.QIPServer + "/api/v1/" + test.QIPOrg
+
+	httpmock.RegisterResponder("GET", baseURL+"/v4address/192.0.2.50.json",
+		httpmock.NewStringResponder(200, `{"objectAddr":"192.0.2.50","subnetAddr":"192.0.2.0","objectName":"test-host",
+			"objectDesc":"keep me","macAddr":"00:11:22:33:44:55","dynamicConfig":"Manual DHCP","aliases":"alias",
+			"nodeId":"42","uniqueNodeId":"u-42","externalTimestamp":"1700000000","manualFlag":"true",
+			"localManualFlag":"true","isCheckDupName":"true"}`))
+	httpmock.RegisterResponder("GET", baseURL+"/rr.json",
+		httpmock.NewStringResponder(200, `{"list":[{"owner":"extra.example.com","rrType":"A","data1":"192.0.2.50",
+			"infraType":"OBJECT","infraAddr":"192.0.2.50"}]}`))
+
+	var (
+		// names and MAC addresses of the objects in QIP by address, QIP rejects duplicates
+		names   = map[string]string{"192.0.2.50": "test-host"}
+		macs    = map[string]string{"192.0.2.50": "00:11:22:33:44:55"}
+		created v4address.V4Address
+	)
+
+	isDuplicate := func(addr *v4address.V4Address) bool {
+		for address, name := range names {
+			if address != addr.ObjectAddr && name == addr.ObjectName && addr.IsCheckDupName == "true" {
+				return true
+			}
+		}
+
+		for address, mac := range macs {
+			if address != addr.ObjectAddr && mac != "" && mac == addr.MacAddr {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	httpmock.RegisterResponder("PUT", baseURL+"/v4address",
+		func(req *http.Request) (*http.Response, error) {
+			var updated v4address.V4Address
+			if err := json.NewDecoder(req.Body).Decode(&updated); err != nil || isDuplicate(&updated) {
+				return httpmock.NewStringResponse(400, `{"error":"duplicate name"}`), nil //nolint:nilerr
+			}
+
+			names[updated.ObjectAddr] = updated.ObjectName
+			macs[updated.ObjectAddr] = updated.MacAddr
+
+			return httpmock.NewStringResponse(200, ""), nil
+		})
+	httpmock.RegisterResponder("POST", baseURL+"/v4address",
+		func(req *http.Request) (*http.Response, error) {
+			if err := json.NewDecoder(req.Body).Decode(&created); err != nil || isDuplicate(&created) {
+				return httpmock.NewStringResponse(400, `{"error":"duplicate name"}`), nil //nolint:nilerr
+			}
+
+			names[created.ObjectAddr] = created.ObjectName
+			macs[created.ObjectAddr] = created.MacAddr
+
+			return httpmock.NewStringResponse(200, ""), nil
+		})
+
+	var updatedRecord map[string]*rr.RR
+
+	httpmock.RegisterResponder("PUT", baseURL+"/rr",
+		func(req *http.Request) (*http.Response, error) {
+			if err := json.NewDecoder(req.Body).Decode(&updatedRecord); err != nil {
+				return httpmock.NewStringResponse(400, ""), nil //nolint:nilerr
+			}
+
+			return httpmock.NewStringResponse(200, ""), nil
+		})
+	httpmock.RegisterResponder("DELETE", baseURL+"/v4address/192.0.2.50/",
+		httpmock.NewStringResponder(200, ""))
+
+	moved, err := v4address.Move(c, "192.0.2.50", "198.51.100.7", "198.51.100.0")
+	require.NoError(t, err)
+	assert.Equal(t, "198.51.100.7", moved.ObjectAddr)
+	assert.Equal(t, "198.51.100.0", created.SubnetAddr)
+	assert.Equal(t, "test-host", created.ObjectName)
+	assert.Equal(t, "keep me", created.ObjectDesc)
+	assert.Equal(t, "00:11:22:33:44:55", created.MacAddr)
+	assert.Equal(t, "Manual DHCP", created.DynamicConfig)
+	assert.Equal(t, "alias", created.Aliases)
+	assert.Equal(t, "test-host-moving", names["192.0.2.50"])
+	assert.Empty(t, macs["192.0.2.50"])
+
+	// Assigned by QIP to the old object
+	assert.Empty(t, created.NodeId)
+	assert.Empty(t, created.UniqueNodeId)
+	assert.Empty(t, created.ExternalTimestamp)
+	assert.Empty(t, created.ManualFlag)
+	assert.Empty(t, created.LocalManualFlag)
+
+	if assert.Contains(t, updatedRecord, "updatedRRRec") {
+		assert.Equal(t, "198.51.100.7", updatedRecord["updatedRRRec"].InfraAddr)
+		assert.Equal(t, "198.51.100.7", updatedRecord["updatedRRRec"].Data1)
+	}
+}
+
+func TestMove_RollbackOnRRFailure(t *testing.T) {
+	c, cleanup := test.GetTestClient(t)
+	defer cleanup()
+
+	baseURL := test.QIPServer + "/api/v1/" + test.QIPOrg
+
+	httpmock.RegisterResponder("GET", baseURL+"/v4address/192.0.2.50.json",
+		httpmock.NewStringResponder(200, `{"objectAddr":"192.0.2.50","subnetAddr":"192.0.2.0","objectName":"test-host"}`))
+	httpmock.RegisterResponder("GET", baseURL+"/rr.json",
+		httpmock.NewStringResponder(200, `{"list":[{"owner":"extra.example.com","rrType":"A","data1":"192.0.2.50",
+			"infraType":"OBJECT","infraAddr":"192.0.2.50"}]}`))
+	httpmock.RegisterResponder("POST", baseURL+"/v4address", httpmock.NewStringResponder(200, ""))
+	httpmock.RegisterResponder("PUT", baseURL+"/rr", httpmock.NewStringResponder(400, `{"error":"bad"}`))
+
+	var names []string
+
+	httpmock.RegisterResponder("PUT", baseURL+"/v4address",
+		func(req *http.Request) (*http.Response, error) {
+			var updated v4address.V4Address
+			if err := json.NewDecoder(req.Body).Decode(&updated); err != nil {
+				return httpmock.NewStringResponse(400, ""), nil //nolint:nilerr
+			}
+
+			names = append(names, updated.ObjectName)
+
+			return httpmock.NewStringResponse(200, ""), nil
+		})
+	httpmock.RegisterResponder("DELETE", baseURL+"/v4address/192.0.2.51/", httpmock.NewStringResponder(200, ""))
+
+	_, err := v4address.Move(c, "192.0.2.50", "192.0.2.51", "")
+	require.Error(t, err)
+
+	info := httpmock.GetCallCountInfo()
+	assert.Equal(t, 1, info["DELETE "+baseURL+"/v4address/192.0.2.51/"])
+	assert.Equal(t, 0, info["DELETE "+baseURL+"/v4address/192.0.2.50/"])
+	assert.Equal(t, []string{"test-host-moving", "test-host"}, names)
+}
@@ -0,0 +1,253 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v4address
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip"
+)
+
+var (
+	ErrUnknownField           = errors.New("unknown V4Address field")
+	ErrConcurrentModification = errors.New("V4Address was modified since it was last read")
+)
+
+// fields maps the JSON name of each V4Address field to an accessor, it must list all fields of the type.
+var fields = map[string]func(addr *V4Address) *string{ //nolint:gochecknoglobals
+	"objectAddr":               func(addr *V4Address) *string { return &addr.ObjectAddr },
+	"subnetAddr":               func(addr *V4Address) *string { return &addr.SubnetAddr },
+	"objectName":               func(addr *V4Address) *string { return &addr.ObjectName },
+	"objectClass":              func(addr *V4Address) *string { return &addr.ObjectClass },
+	"domainName":               func(addr *V4Address) *string { return &addr.DomainName },
+	"expiredDate":              func(addr *V4Address) *string { return &addr.ExpiredDate },
+	"serverType":               func(addr *V4Address) *string { return &addr.ServerType },
+	"applName":                 func(addr *V4Address) *string { return &addr.ApplName },
+	"objectTag":                func(addr *V4Address) *string { return &addr.ObjectTag },
+	"roomId":                   func(addr *V4Address) *string { return &addr.RoomId },
+	"manufacturer":             func(addr *V4Address) *string { return &addr.Manufacturer },
+	"modelType":                func(addr *V4Address) *string { return &addr.ModelType },
+	"serialNumber":             func(addr *V4Address) *string { return &addr.SerialNumber },
+	"assetNumber":              func(addr *V4Address) *string { return &addr.AssetNumber },
+	"hostId":                   func(addr *V4Address) *string { return &addr.HostId },
+	"purchaseDate":             func(addr *V4Address) *string { return &addr.PurchaseDate },
+	"objectDesc":               func(addr *V4Address) *string { return &addr.ObjectDesc },
+	"hubName":                  func(addr *V4Address) *string { return &addr.HubName },
+	"slotName":                 func(addr *V4Address) *string { return &addr.SlotName },
+	"portNumber":               func(addr *V4Address) *string { return &addr.PortNumber },
+	"locationId":               func(addr *V4Address) *string { return &addr.LocationId },
+	"street1":                  func(addr *V4Address) *string { return &addr.Street1 },
+	"street2":                  func(addr *V4Address) *string { return &addr.Street2 },
+	"city":                     func(addr *V4Address) *string { return &addr.City },
+	"state":                    func(addr *V4Address) *string { return &addr.State },
+	"zip":                      func(addr *V4Address) *string { return &addr.Zip },
+	"country":                  func(addr *V4Address) *string { return &addr.Country },
+	"contactId":                func(addr *V4Address) *string { return &addr.ContactId },
+	"contactLastName":          func(addr *V4Address) *string { return &addr.ContactLastName },
+	"contactFirstName":         func(addr *V4Address) *string { return &addr.ContactFirstName },
+	"contactEmail":             func(addr *V4Address) *string { return &addr.ContactEmail },
+	"contactPhone":             func(addr *V4Address) *string { return &addr.ContactPhone },
+	"contactPager":             func(addr *V4Address) *string { return &addr.ContactPager },
+	"routerGroup":              func(addr *V4Address) *string { return &addr.RouterGroup },
+	"dynamicConfig":            func(addr *V4Address) *string { return &addr.DynamicConfig },
+	"macAddr":                  func(addr *V4Address) *string { return &addr.MacAddr },
+	"tftpServer":               func(addr *V4Address) *string { return &addr.TftpServer },
+	"bootFileName":             func(addr *V4Address) *string { return &addr.BootFileName },
+	"hardwareType":             func(addr *V4Address) *string { return &addr.HardwareType },
+	"aliases":                  func(addr *V4Address) *string { return &addr.Aliases },
+	"mailForwarders":           func(addr *V4Address) *string { return &addr.MailForwarders },
+	"mailHosts":                func(addr *V4Address) *string { return &addr.MailHosts },
+	"hubSlots":                 func(addr *V4Address) *string { return &addr.HubSlots },
+	"dnsServers":               func(addr *V4Address) *string { return &addr.DnsServers },
+	"timeServers":              func(addr *V4Address) *string { return &addr.TimeServers },
+	"defaultRouters":           func(addr *V4Address) *string { return &addr.DefaultRouters },
+	"userClasses":              func(addr *V4Address) *string { return &addr.UserClasses },
+	"users":                    func(addr *V4Address) *string { return &addr.Users },
+	"nameService":              func(addr *V4Address) *string { return &addr.NameService },
+	"dynamicDnsUpdate":         func(addr *V4Address) *string { return &addr.DynamicDnsUpdate },
+	"dhcpServer":               func(addr *V4Address) *string { return &addr.DhcpServer },
+	"dhcpOptionTemplate":       func(addr *V4Address) *string { return &addr.DhcpOptionTemplate },
+	"dhcpPolicyTemplate":       func(addr *V4Address) *string { return &addr.DhcpPolicyTemplate },
+	"leaseTime":                func(addr *V4Address) *string { return &addr.LeaseTime },
+	"ttlTime":                  func(addr *V4Address) *string { return &addr.TTLTime },
+	"vendorClass":              func(addr *V4Address) *string { return &addr.VendorClass },
+	"clientId":                 func(addr *V4Address) *string { return &addr.ClientId },
+	"dualProtocol":             func(addr *V4Address) *string { return &addr.DualProtocol },
+	"decNetArea":               func(addr *V4Address) *string { return &addr.DecNetArea },
+	"decNetAddr":               func(addr *V4Address) *string { return &addr.DecNetAddr },
+	"decNetNode":               func(addr *V4Address) *string { return &addr.DecNetNode },
+	"talkType":                 func(addr *V4Address) *string { return &addr.TalkType },
+	"ipxNode":                  func(addr *V4Address) *string { return &addr.IpxNode },
+	"ipxNetworkNumber":         func(addr *V4Address) *string { return &addr.IpxNetworkNumber },
+	"netBiosDomain":            func(addr *V4Address) *string { return &addr.NetBiosDomain },
+	"netBiosName":              func(addr *V4Address) *string { return &addr.NetBiosName },
+	"usageBillServices":        func(addr *V4Address) *string { return &addr.UsageBillServices },
+	"usageBillLocation":        func(addr *V4Address) *string { return &addr.UsageBillLocation },
+	"usageBillUserGroup":       func(addr *V4Address) *string { return &addr.UsageBillUserGroup },
+	"usageBillObjectClass":     func(addr *V4Address) *string { return &addr.UsageBillObjectClass },
+	"allowModifyDynamicRRs":    func(addr *V4Address) *string { return &addr.AllowModifyDynamicRRs },
+	"tombstoned":               func(addr *V4Address) *string { return &addr.Tombstoned },
+	"externalComment":          func(addr *V4Address) *string { return &addr.ExternalComment },
+	"externalTimestamp":        func(addr *V4Address) *string { return &addr.ExternalTimestamp },
+	"manualFlag":               func(addr *V4Address) *string { return &addr.ManualFlag },
+	"nodeId":                   func(addr *V4Address) *string { return &addr.NodeId },
+	"uniqueNodeId":             func(addr *V4Address) *string { return &addr.UniqueNodeId },
+	"aTTL":                     func(addr *V4Address) *string { return &addr.ATTL },
+	"ptrTTL":                   func(addr *V4Address) *string { return &addr.PtrTTL },
+	"publishA":                 func(addr *V4Address) *string { return &addr.PublishA },
+	"publishPTR":               func(addr *V4Address) *string { return &addr.PublishPTR },
+	"dhcpClientClass":          func(addr *V4Address) *string { return &addr.DhcpClientClass },
+	"isUpdate":                 func(addr *V4Address) *string { return &addr.IsUpdate },
+	"isAddSelected":            func(addr *V4Address) *string { return &addr.IsAddSelected },
+	"isCheckDupName":           func(addr *V4Address) *string { return &addr.IsCheckDupName },
+	"isCheckOnlyFQDNDups":      func(addr *V4Address) *string { return &addr.IsCheckOnlyFQDNDups },
+	"isSwapAliasAndObjectName": func(addr *V4Address) *string { return &addr.IsSwapAliasAndObjectName },
+	"localManualFlag":          func(addr *V4Address) *string { return &addr.LocalManualFlag },
+}
+
+// requestFlagFields only control how QIP handles a request, they are not compared with a base object.
+var requestFlagFields = []string{ //nolint:gochecknoglobals
+	"isUpdate", "isAddSelected", "isCheckDupName", "isCheckOnlyFQDNDups", "isSwapAliasAndObjectName",
+}
+
+// Patch updates only the fields listed in fieldMask and keeps all other attributes of the object.
+//
+// Fields are named like the JSON attributes of V4Address (e.g. objectName or domainName), the values
+// are taken from changes. base is the object as the caller last saw it, e.g. from a prior state, and
+// may be nil. When a field of the mask differs between base and the loaded object, someone else changed
+// it in between and ErrConcurrentModification is returned instead of overwriting that change.
+// No update is sent when the fields already have the requested values.
+func Patch(client *qip.Client, address string, base, changes *V4Address, fieldMask []string) (*V4Address, error) {
+	err := checkFields(fieldMask)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := Load(client, address)
+	if err != nil {
+		return nil, err
+	}
+
+	if base != nil {
+		if modified := modifiedFields(base, current, fieldMask); len(modified) > 0 {
+			return nil, fmt.Errorf("%w: %s changed %s", ErrConcurrentModification, address, strings.Join(modified, ", "))
+		}
+	}
+
+	patched := *current
+
+	err = ApplyFields(&patched, changes, fieldMask)
+	if err != nil {
+		return nil, err
+	}
+
+	if patched == *current {
+		return current, nil
+	}
+
+	err = Update(client, &patched)
+	if err != nil {
+		return nil, err
+	}
+
+	return &patched, nil
+}
+
+// ApplyFields copies the fields listed in fieldMask from changes to addr, empty values clear the field.
+func ApplyFields(addr, changes *V4Address, fieldMask []string) error {
+	err := checkFields(fieldMask)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range fieldMask {
+		field := fields[name]
+		*field(addr) = *field(changes)
+	}
+
+	return nil
+}
+
+// checkFields checks that all names are exactly the JSON name of a field of V4Address.
+func checkFields(fieldMask []string) error {
+	for _, name := range fieldMask {
+		if _, ok := fields[name]; !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownField, name)
+		}
+	}
+
+	return nil
+}
+
+// modifiedFields returns the fields of the mask, that have a different value in base and current.
+func modifiedFields(base, current *V4Address, fieldMask []string) []string {
+	var modified []string
+
+	for _, name := range fieldMask {
+		if slices.Contains(requestFlagFields, name) {
+			continue
+		}
+
+		field := fields[name]
+		if !fieldValueEqual(name, *field(base), *field(current)) {
+			modified = append(modified, name)
+		}
+	}
+
+	return modified
+}
+
+// fieldValueEqual compares two values of a field, ignoring differences in how QIP formats them.
+func fieldValueEqual(name, a, b string) bool {
+	a, b = normalizeFieldValue(a), normalizeFieldValue(b)
+
+	switch name {
+	case "objectName", "domainName":
+		return strings.EqualFold(strings.TrimSuffix(a, "."), strings.TrimSuffix(b, "."))
+	case "macAddr":
+		macA, errA := NormalizeMACAddress(a)
+		macB, errB := NormalizeMACAddress(b)
+
+		if errA == nil && errB == nil {
+			return macA == macB
+		}
+	case "expiredDate":
+		expiresA, okA, errA := (&V4Address{ExpiredDate: a}).ExpiresAt()
+		expiresB, okB, errB := (&V4Address{ExpiredDate: b}).ExpiresAt()
+
+		if errA == nil && errB == nil {
+			return okA == okB && expiresA.Equal(expiresB)
+		}
+	}
+
+	return a == b
+}
+
+// normalizeFieldValue trims the value and treats the QIP placeholder for unset values as empty.
+func normalizeFieldValue(value string) string {
+	value = strings.TrimSpace(value)
+	if value == "None" {
+		return ""
+	}
+
+	return value
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v4address_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/test"
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/v4address"
+)
+
+func TestPatch(t *testing.T) {
+	c, cleanup := test.GetTestClient(t)
+	defer cleanup()
+
+	baseURL := test.QIPServer + "/api/v1/" + test.QIPOrg
+
+	httpmock.RegisterResponder("GET", baseURL+"/v4address/192.0.2.10.json",
+		httpmock.NewStringResponder(200, `{"objectAddr":"192.0.2.10","subnetAddr":"192.0.2.0","objectName":"host",
+			"domainName":"example.com","macAddr":"00:11:22:33:44:55"}`))
+
+	var updated v4address.V4Address
+
+	httpmock.RegisterResponder("PUT", baseURL+"/v4address",
+		func(req *http.Request) (*http.Response, error) {
+			if err := json.NewDecoder(req.Body).Decode(&updated); err != nil {
+				return httpmock.NewStringResponse(400, ""), nil //nolint:nilerr
+			}
+
+			return httpmock.NewStringResponse(200, ""), nil
+		})
+
+	changes := &v4address.V4Address{ObjectName: "renamed", DomainName: "ignored.example.com"}
+
+	patched, err := v4address.Patch(c, "192.0.2.10", nil, changes, []string{"objectName"})
+	require.NoError(t, err)
+	assert.Equal(t, "renamed", patched.ObjectName)
+	assert.Equal(t, "renamed", updated.ObjectName)
+	assert.Equal(t, "example.com", updated.DomainName)
+	assert.Equal(t, "00:11:22:33:44:55", updated.MacAddr)
+
+	// Empty values clear the field
+	updated = v4address.V4Address{}
+
+	_, err = v4address.Patch(c, "192.0.2.10", nil, &v4address.V4Address{}, []string{"domainName"})
+	require.NoError(t, err)
+	assert.Empty(t, updated.DomainName)
+	assert.Equal(t, "host", updated.ObjectName)
+
+	// Nothing to change
+	httpmock.ZeroCallCounters()
+
+	_, err = v4address.Patch(c, "192.0.2.10", nil, &v4address.V4Address{ObjectName: "host"}, []string{"objectName"})
+	require.NoError(t, err)
+	assert.Equal(t, 0, httpmock.GetCallCountInfo()["PUT "+baseURL+"/v4address"])
+
+	_, err = v4address.Patch(c, "192.0.2.10", nil, changes, []string{"noSuchField"})
+	require.ErrorIs(t, err, v4address.ErrUnknownField)
+
+	_, err = v4address.Patch(c, "192.0.2.10", nil, changes, []string{"objectname"})
+	require.ErrorIs(t, err, v4address.ErrUnknownField)
+}
+
+func TestPatch_ConcurrentModification(t *testing.T) {
+	c, cleanup := test.GetTestClient(t)
+	defer cleanup()
+
+	baseURL := test.QIPServer + "/api/v1/" + test.QIPOrg
+
+	httpmock.RegisterResponder("GET", baseURL+"/v4address/192.0.2.10.json",
+		httpmock.NewStringResponder(200, `{"objectAddr":"192.0.2.10","subnetAddr":"192.0.2.0","objectName":"host",
+			"domainName":"Example.com.","objectDesc":"changed","macAddr":"00-11-22-33-44-55","contactEmail":"None"}`))
+	httpmock.RegisterResponder("PUT", baseURL+"/v4address", httpmock.NewStringResponder(200, ""))
+
+	base := &v4address.V4Address{
+		ObjectName: "host",
+		DomainName: "example.com",
+		MacAddr:    "00:11:22:33:44:55",
+	}
+	changes := &v4address.V4Address{ObjectName: "renamed"}
+	mask := []string{"objectName", "domainName", "macAddr", "contactEmail", "objectDesc", "isCheckDupName"}
+
+	// objectDesc was changed since base was read
+	_, err := v4address.Patch(c, "192.0.2.10", base, changes, mask)
+	require.ErrorIs(t, err, v4address.ErrConcurrentModification)
+	assert.Contains(t, err.Error(), "objectDesc")
+	assert.Equal(t, 0, httpmock.GetCallCountInfo()["PUT "+baseURL+"/v4address"])
+
+	// Formatting differences of QIP are not a modification
+	base.ObjectDesc = "changed"
+
+	patched, err := v4address.Patch(c, "192.0.2.10", base, changes, mask)
+	require.NoError(t, err)
+	assert.Equal(t, "renamed", patched.ObjectName)
+	assert.Equal(t, 1, httpmock.GetCallCountInfo()["PUT "+baseURL+"/v4address"])
+}
+
+// TestApplyFields_AllFields checks that every field of the generated type can be patched.
+func TestApplyFields_AllFields(t *testing.T) {
+	data, err := os.ReadFile("v4address.json")
+	require.NoError(t, err)
+
+	var definition map[string]string
+	require.NoError(t, json.Unmarshal(data, &definition))
+
+	for name := range definition {
+		err = v4address.ApplyFields(&v4address.V4Address{}, &v4address.V4Address{}, []string{name})
+		require.NoError(t, err, name)
+	}
+
+	addr := &v4address.V4Address{ObjectName: "host", ObjectDesc: "keep"}
+
+	err = v4address.ApplyFields(addr, &v4address.V4Address{LocalManualFlag: "true"}, []string{"objectName", "localManualFlag"})
+	require.NoError(t, err)
+	assert.Equal(t, v4address.V4Address{ObjectDesc: "keep", LocalManualFlag: "true"}, *addr)
+}
@@ -41,13 +41,26 @@ var ErrNoSelection = errors.New("no object address was returned")
 //
 // If you don't want to create the IP, you need to free it, not sure if it will expire.
 func CreateSelected(client *qip.Client, subnet string, addrs *SelectedAddrRange) (string, error) {
-	var body any
+	var ranges []*SelectedAddrRange
 
 	if addrs != nil {
+		ranges = []*SelectedAddrRange{addrs}
+	}
+
+	return CreateSelectedInRanges(client, subnet, ranges)
+}
+
+// CreateSelectedInRanges reserves a new address within one of the ranges and returns the objectAddr.
+//
+// QIP will pick the address, without any ranges the whole subnet is considered.
+func CreateSelectedInRanges(client *qip.Client, subnet string, ranges []*SelectedAddrRange) (string, error) {
+	var body any
+
+	if len(ranges) > 0 {
 		body = struct {
 			AddrRange []*SelectedAddrRange `json:"addrRange"`
 		}{
-			AddrRange: []*SelectedAddrRange{addrs},
+			AddrRange: ranges,
 		}
 	}
 
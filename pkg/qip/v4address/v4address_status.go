@@ -0,0 +1,90 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v4address
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip"
+)
+
+// State describes how an address is used in QIP.
+type State string
+
+const (
+	// StateFree means no object exists and the address is not selected.
+	StateFree State = "free"
+	// StateSelected means the address is reserved by CreateSelected, but no object was created yet.
+	StateSelected State = "selected"
+	// StateInUse means an object exists for the address.
+	StateInUse State = "in_use"
+	// StateTombstoned means the object was deleted, but is kept by QIP until it is purged.
+	StateTombstoned State = "tombstoned"
+)
+
+// States lists all values of State.
+var States = []State{StateFree, StateSelected, StateInUse, StateTombstoned}
+
+// Status returns the state of an address, and the object when one exists.
+//
+// The state is derived from the v4address object: QIP keeps a selected address as an object
+// without a name until it is converted by Update, see IsSelection.
+func Status(client *qip.Client, address string) (State, *V4Address, error) {
+	addr, err := LoadWithOptions(client, address, &LoadOptions{IncludeTombstoned: true})
+	if err != nil {
+		var notFoundErr *qip.HTTPNotFoundError
+		if errors.As(err, &notFoundErr) {
+			return StateFree, nil, nil
+		}
+
+		return "", nil, err
+	}
+
+	switch {
+	case addr.IsTombstoned():
+		return StateTombstoned, addr, nil
+	case addr.IsSelection():
+		return StateSelected, nil, nil
+	default:
+		return StateInUse, addr, nil
+	}
+}
+
+// IsSelected checks if an address is reserved by CreateSelected.
+//
+// Addresses with an object are not reported as selected.
+func IsSelected(client *qip.Client, address string) (bool, error) {
+	state, _, err := Status(client, address)
+	if err != nil {
+		return false, err
+	}
+
+	return state == StateSelected, nil
+}
+
+// IsSelection checks if the object is only the reservation of a selected address.
+//
+// CreateSelected returns the reservation like an object with only the address set, and every
+// created object requires a name (see Create), so an object without a name is a selection.
+func (addr *V4Address) IsSelection() bool {
+	name := strings.TrimSpace(addr.ObjectName)
+
+	return name == "" || name == "None"
+}
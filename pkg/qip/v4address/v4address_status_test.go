@@ -0,0 +1,74 @@
+/*
+Copyright 2024 Vitesco Technologies Group AG
+
+SPDX-License-Identifier: Apache-2.0
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v4address_test
+
+import (
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/test"
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/v4address"
+)
+
+func TestStatus(t *testing.T) {
+	c, cleanup := test.GetTestClient(t)
+	defer cleanup()
+
+	baseURL := test.QIPServer + "/api/v1/" + test.QIPOrg
+
+	httpmock.RegisterResponder("GET", baseURL+"/v4address/192.0.2.1.json",
+		httpmock.NewStringResponder(200, `{"objectAddr":"192.0.2.1","objectName":"host"}`))
+	httpmock.RegisterResponder("GET", baseURL+"/v4address/192.0.2.2.json",
+		httpmock.NewStringResponder(200, `{"objectAddr":"192.0.2.2","objectName":"old","tombstoned":"true"}`))
+	httpmock.RegisterResponder("GET", baseURL+"/v4address/192.0.2.3.json",
+		httpmock.NewStringResponder(200, `{"objectAddr":"192.0.2.3","subnetAddr":"192.0.2.0","objectName":"None",
+			"objectClass":"None","domainName":"None","dynamicConfig":"Static"}`))
+	httpmock.RegisterResponder("GET", baseURL+"/v4address/192.0.2.4.json",
+		httpmock.NewStringResponder(404, `{"error":"IP address 192.0.2.4 does not have an object associated with it"}`))
+
+	tests := map[string]v4address.State{
+		"192.0.2.1": v4address.StateInUse,
+		"192.0.2.2": v4address.StateTombstoned,
+		"192.0.2.3": v4address.StateSelected,
+		"192.0.2.4": v4address.StateFree,
+	}
+
+	for address, expected := range tests {
+		state, addr, err := v4address.Status(c, address)
+		require.NoError(t, err, address)
+		assert.Equal(t, expected, state, address)
+
+		if expected == v4address.StateInUse || expected == v4address.StateTombstoned {
+			assert.NotNil(t, addr, address)
+		} else {
+			assert.Nil(t, addr, address)
+		}
+	}
+
+	selected, err := v4address.IsSelected(c, "192.0.2.3")
+	require.NoError(t, err)
+	assert.True(t, selected)
+
+	selected, err = v4address.IsSelected(c, "192.0.2.1")
+	require.NoError(t, err)
+	assert.False(t, selected)
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip"
 	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/test"
 	"github.com/Vitesco-Technologies/terraform-provider-qip/pkg/qip/v4address"
 )
@@ -42,6 +43,24 @@ func TestLoad(t *testing.T) {
 	assert.Equal(t, "192.0.2.50", addr.ObjectAddr)
 }
 
+func TestLoad_Tombstoned(t *testing.T) {
+	c, cleanup := test.GetTestClient(t)
+	defer cleanup()
+
+	httpmock.RegisterResponder("GET", test.QIPServer+"/api/v1/"+test.QIPOrg+"/v4address/192.0.2.50.json",
+		httpmock.NewStringResponder(200, `{"objectAddr":"192.0.2.50","objectName":"test-host","tombstoned":"true"}`))
+
+	_, err := v4address.Load(c, "192.0.2.50")
+	require.ErrorIs(t, err, qip.ErrTombstoned)
+
+	var notFoundErr *qip.HTTPNotFoundError
+	assert.ErrorAs(t, err, &notFoundErr)
+
+	addr, err := v4address.LoadWithOptions(c, "192.0.2.50", &v4address.LoadOptions{IncludeTombstoned: true})
+	require.NoError(t, err)
+	assert.True(t, addr.IsTombstoned())
+}
+
 func TestCreate(t *testing.T) {
 	c, cleanup := test.GetTestClient(t)
 	defer cleanup()